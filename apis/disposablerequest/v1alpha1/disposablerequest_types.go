@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 )
 
 // DisposableRequestParameters are the configurable fields of a DisposableRequest.
@@ -44,6 +46,31 @@ type DisposableRequestParameters struct {
 	// InsecureSkipTLSVerify, when set to true, skips TLS certificate checks for the HTTP request
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
 
+	// TLSConfig configures the TLS settings of the HTTP requests. Fields set
+	// here override those of the ProviderConfig.
+	// +optional
+	TLSConfig *apisv1alpha1.TLSConfig `json:"tlsConfig,omitempty"`
+
+	// Retry overrides the retry policy of the ProviderConfig for this
+	// request.
+	// +optional
+	Retry *apisv1alpha1.RetryPolicy `json:"retry,omitempty"`
+
+	// HMACSigner overrides the HMAC signer of the ProviderConfig for this
+	// request.
+	// +optional
+	HMACSigner *apisv1alpha1.HMACSigner `json:"hmacSigner,omitempty"`
+
+	// StatusMasking configures the values replaced with *** in the status,
+	// in addition to those of the ProviderConfig.
+	// +optional
+	StatusMasking *apisv1alpha1.StatusMasking `json:"statusMasking,omitempty"`
+
+	// ConnectionDetails are published to the connection Secret of the
+	// resource, see writeConnectionSecretToRef.
+	// +optional
+	ConnectionDetails []apisv1alpha1.ConnectionDetail `json:"connectionDetails,omitempty"`
+
 	// ExpectedResponse is a jq filter expression used to evaluate the HTTP response and determine if it matches the expected criteria.
 	// The expression should return a boolean; if true, the response is considered expected.
 	// Example: '.Body.job_status == "success"'
@@ -68,6 +95,9 @@ type Mapping struct {
 	Body    string              `json:"body,omitempty"`
 	URL     string              `json:"url"`
 	Headers map[string][]string `json:"headers,omitempty"`
+
+	// Proxy is the proxy the request was sent through, if any.
+	Proxy string `json:"proxy,omitempty"`
 }
 
 // A DisposableRequestStatus represents the observed state of a DisposableRequest.
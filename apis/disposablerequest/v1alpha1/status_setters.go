@@ -26,9 +26,10 @@ func (d *DisposableRequest) SetError(err error) {
 	}
 }
 
-func (d *DisposableRequest) SetRequestDetails(url, method, body string, headers map[string][]string) {
+func (d *DisposableRequest) SetRequestDetails(url, method, body, proxy string, headers map[string][]string) {
 	d.Status.RequestDetails.Body = body
 	d.Status.RequestDetails.URL = url
 	d.Status.RequestDetails.Headers = headers
 	d.Status.RequestDetails.Method = method
+	d.Status.RequestDetails.Proxy = proxy
 }
@@ -22,6 +22,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -113,6 +114,31 @@ func (in *DisposableRequestParameters) DeepCopyInto(out *DisposableRequestParame
 		*out = new(int32)
 		**out = **in
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(apisv1alpha1.TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(apisv1alpha1.RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HMACSigner != nil {
+		in, out := &in.HMACSigner, &out.HMACSigner
+		*out = new(apisv1alpha1.HMACSigner)
+		**out = **in
+	}
+	if in.StatusMasking != nil {
+		in, out := &in.StatusMasking, &out.StatusMasking
+		*out = new(apisv1alpha1.StatusMasking)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make([]apisv1alpha1.ConnectionDetail, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DisposableRequestParameters.
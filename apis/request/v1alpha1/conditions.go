@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeDeletionVerified indicates whether the deletion of the resource of a
+// Request was confirmed.
+const TypeDeletionVerified xpv1.ConditionType = "DeletionVerified"
+
+// ReasonResourceStillExists indicates the resource still exists after its
+// deletion was requested.
+const ReasonResourceStillExists xpv1.ConditionReason = "ResourceStillExists"
+
+// DeletionNotVerified returns a condition that indicates the resource still
+// exists longer than expected after its deletion was requested.
+func DeletionNotVerified(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDeletionVerified,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonResourceStillExists,
+		Message:            err.Error(),
+	}
+}
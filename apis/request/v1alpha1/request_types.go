@@ -23,6 +23,8 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 )
 
 // RequestParameters are the configurable fields of a Request.
@@ -35,14 +37,193 @@ type RequestParameters struct {
 
 	// InsecureSkipTLSVerify, when set to true, skips TLS certificate checks for the HTTP request
 	InsecureSkipTLSVerify bool `json:"insecureSkipTLSVerify,omitempty"`
+
+	// TLSConfig configures the TLS settings of the HTTP requests. Fields set
+	// here override those of the ProviderConfig.
+	// +optional
+	TLSConfig *apisv1alpha1.TLSConfig `json:"tlsConfig,omitempty"`
+
+	// StatusMasking configures the values replaced with *** in the status,
+	// in addition to those of the ProviderConfig.
+	// +optional
+	StatusMasking *apisv1alpha1.StatusMasking `json:"statusMasking,omitempty"`
+
+	// ConnectionDetails are published to the connection Secret of the
+	// resource, see writeConnectionSecretToRef.
+	// +optional
+	ConnectionDetails []apisv1alpha1.ConnectionDetail `json:"connectionDetails,omitempty"`
+
+	// IsUpToDate is a jq expression deciding whether the observed state is
+	// up to date, for APIs whose read and write shapes differ. It gets the
+	// body of the PUT mapping as .desiredState and the response of the GET
+	// mapping, with its statusCode, headers and body, as .response, and
+	// returns a boolean, e.g. .response.body.user.name == .desiredState.username.
+	// By default the body of the PATCH mapping, or of the PUT mapping
+	// without one, must be contained in the GET response body.
+	// +optional
+	IsUpToDate string `json:"isUpToDate,omitempty"`
+
+	// Comparison configures the rules applied to both the GET response body
+	// and the body of the PUT mapping before they are compared, when no
+	// isUpToDate expression is set.
+	// +optional
+	Comparison *Comparison `json:"comparison,omitempty"`
+
+	// DeletionVerification, when set, keeps the Request once the DELETE
+	// mapping was sent until the GET mapping, with its existence check,
+	// confirms the resource is gone. The DELETE mapping isn't sent again
+	// meanwhile.
+	// +optional
+	DeletionVerification *DeletionVerification `json:"deletionVerification,omitempty"`
+
+	// Adoption, when set, looks up an existing resource before the POST
+	// mapping is sent. A resource found is adopted: the response of the
+	// lookup is stored in the status as if the POST mapping returned it.
+	// +optional
+	Adoption *Adoption `json:"adoption,omitempty"`
 }
 
+// Adoption configures how existing resources are found. The expressions of the
+// mappings can reference the crossplane.io/external-name annotation of the
+// Request as .externalName.
+type Adoption struct {
+	// Lookup is the mapping sent to find the resource, e.g. a GET searching
+	// resources by name. Its existence check, if any, applies. Defaults to
+	// the GET mapping.
+	// +optional
+	Lookup *Mapping `json:"lookup,omitempty"`
+
+	// Selector is a jq expression evaluated against the response of the
+	// lookup, with its statusCode, headers and body, returning the body of
+	// the resource found or null, e.g.
+	// first(.body.items[] | select(.name == "web")) // null. Defaults to the
+	// body of successful responses showing the resource exists.
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// OnConflict looks the resource up again when the POST mapping is
+	// answered with 409 Conflict, adopting it instead of failing.
+	// +optional
+	OnConflict bool `json:"onConflict,omitempty"`
+}
+
+// DeletionVerification configures how the deletion of a resource is verified.
+type DeletionVerification struct {
+	// Timeout is how long the resource may still exist after the DELETE
+	// mapping was sent before the DeletionVerified condition reports it. The
+	// resource keeps being checked afterwards. Defaults to 10m.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PatchType is the body sent by the PATCH mapping.
+type PatchType string
+
+// Supported PATCH bodies.
+const (
+	PatchTypeBody       PatchType = "Body"
+	PatchTypeMergePatch PatchType = "MergePatch"
+	PatchTypeJSONPatch  PatchType = "JSONPatch"
+)
+
 type Mapping struct {
-	// +kubebuilder:validation:Enum=POST;GET;PUT;DELETE
+	// +kubebuilder:validation:Enum=POST;GET;PUT;PATCH;DELETE
 	Method  string              `json:"method"`
 	Body    string              `json:"body,omitempty"`
 	URL     string              `json:"url"`
 	Headers map[string][]string `json:"headers,omitempty"`
+
+	// Retry overrides the retry policy of the ProviderConfig for this
+	// mapping.
+	// +optional
+	Retry *apisv1alpha1.RetryPolicy `json:"retry,omitempty"`
+
+	// HMACSigner overrides the HMAC signer of the ProviderConfig for this
+	// mapping.
+	// +optional
+	HMACSigner *apisv1alpha1.HMACSigner `json:"hmacSigner,omitempty"`
+
+	// ExistsWhen is a jq expression evaluated against the response of the
+	// GET mapping, with its statusCode, headers and body, returning whether
+	// the resource exists, e.g. (.body.items | length) > 0. Only used on
+	// the GET mapping, where it replaces treating a 404 as not found.
+	// +optional
+	ExistsWhen string `json:"existsWhen,omitempty"`
+
+	// NotFoundWhen is a jq expression evaluated against the response of the
+	// GET mapping, returning whether the resource doesn't exist, e.g.
+	// .statusCode == 410 or .body.deleted == true. Only used on the GET
+	// mapping, where it replaces treating a 404 as not found.
+	// +optional
+	NotFoundWhen string `json:"notFoundWhen,omitempty"`
+
+	// PatchType is the body sent by the PATCH mapping. Body sends the
+	// rendered body. MergePatch sends an RFC 7386 merge patch and JSONPatch
+	// an RFC 6902 JSON Patch, both computed from the GET response to the
+	// rendered body. Only used on the PATCH mapping, defaults to Body.
+	// +kubebuilder:validation:Enum=Body;MergePatch;JSONPatch
+	// +optional
+	PatchType PatchType `json:"patchType,omitempty"`
+
+	// AsyncOperation, when set, follows the asynchronous operation started
+	// by the mapping when the API answers it with 202 Accepted. The
+	// resource isn't observed until the operation finishes.
+	// +optional
+	AsyncOperation *AsyncOperation `json:"asyncOperation,omitempty"`
+}
+
+// AsyncOperation describes how to follow an asynchronous operation. The jq
+// expressions are evaluated against a response, with its statusCode, headers
+// and body.
+type AsyncOperation struct {
+	// URL is a jq expression evaluated against the 202 response, returning
+	// the URL of the operation, e.g. .body.operation.href. Relative URLs are
+	// resolved against the URL of the request. Defaults to the Location
+	// header.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// PollInterval is how often the operation URL is requested. Defaults to
+	// 10s.
+	// +optional
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+
+	// CompletedWhen is a jq expression evaluated against the responses of the
+	// operation URL, returning whether the operation completed, e.g.
+	// .body.status == "Succeeded". Defaults to a successful response other
+	// than 202.
+	// +optional
+	CompletedWhen string `json:"completedWhen,omitempty"`
+
+	// FailedWhen is a jq expression evaluated against the responses of the
+	// operation URL, returning whether the operation failed, e.g.
+	// .body.status == "Failed". Defaults to an HTTP error response.
+	// +optional
+	FailedWhen string `json:"failedWhen,omitempty"`
+
+	// Timeout is how long the operation may run before it is considered
+	// failed. Operations don't time out by default.
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// Comparison describes rules applied to JSON bodies before they are compared,
+// in order: normalize, ignorePaths, then arraysAsSets.
+type Comparison struct {
+	// Normalize is a jq expression rewriting both bodies, e.g.
+	// .size |= tonumber.
+	// +optional
+	Normalize string `json:"normalize,omitempty"`
+
+	// IgnorePaths are jq paths of the values left out of the comparison,
+	// e.g. .updatedAt or .items[].etag.
+	// +optional
+	IgnorePaths []string `json:"ignorePaths,omitempty"`
+
+	// ArraysAsSets are jq paths of arrays compared regardless of the order
+	// and the duplicates of their elements, e.g. .tags or .rules[].ports.
+	// +optional
+	ArraysAsSets []string `json:"arraysAsSets,omitempty"`
 }
 
 type Payload struct {
@@ -66,11 +247,92 @@ type Response struct {
 // A RequestStatus represents the observed state of a Request.
 type RequestStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
-	Response            Response `json:"response,omitempty"`
-	Cache               Cache    `json:"cache,omitempty"`
-	Failed              int32    `json:"failed,omitempty"`
-	Error               string   `json:"error,omitempty"`
-	RequestDetails      Mapping  `json:"requestDetails,omitempty"`
+	Response            Response       `json:"response,omitempty"`
+	Cache               Cache          `json:"cache,omitempty"`
+	Failed              int32          `json:"failed,omitempty"`
+	Error               string         `json:"error,omitempty"`
+	RequestDetails      RequestDetails `json:"requestDetails,omitempty"`
+
+	// Drift describes why the observed state was last found not to be up
+	// to date with the desired state.
+	// +optional
+	Drift *Drift `json:"drift,omitempty"`
+
+	// AsyncOperation is the asynchronous operation the resource waits for,
+	// if any.
+	// +optional
+	AsyncOperation *AsyncOperationStatus `json:"asyncOperation,omitempty"`
+
+	// DeletionRequested is when the DELETE mapping was sent, if the deletion
+	// of the resource is verified.
+	// +optional
+	DeletionRequested *metav1.Time `json:"deletionRequested,omitempty"`
+
+	// Masked lists the fields of the response and the cache masked by the
+	// status masking rules, whose unmasked values are kept in a Secret.
+	// +optional
+	Masked *Masked `json:"masked,omitempty"`
+}
+
+// Masked lists the fields masked in the status, either body or
+// headers.<name>, e.g. headers.X-Token.
+type Masked struct {
+	// Response lists the masked fields of status.response.
+	// +optional
+	Response []string `json:"response,omitempty"`
+
+	// Cache lists the masked fields of status.cache.response.
+	// +optional
+	Cache []string `json:"cache,omitempty"`
+
+	// SecretRef references the Secret keeping the unmasked response and
+	// cache. It is deleted along with the Request.
+	SecretRef xpv1.SecretReference `json:"secretRef"`
+}
+
+// AsyncOperationStatus describes a pending asynchronous operation.
+type AsyncOperationStatus struct {
+	// Method of the mapping that started the operation.
+	Method string `json:"method"`
+
+	// URL requested until the operation finishes.
+	URL string `json:"url"`
+
+	// StartTime is when the operation started.
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// Drift lists the paths, written as jq paths, through which the GET response
+// differs from the body of the PUT mapping. A path of . stands for the whole
+// body, e.g. when it is not JSON or the isUpToDate expression returned false.
+type Drift struct {
+	// Added are the paths of the desired state missing from the response.
+	// +optional
+	Added []string `json:"added,omitempty"`
+
+	// Removed are the paths of the response the desired state doesn't
+	// have.
+	// +optional
+	Removed []string `json:"removed,omitempty"`
+
+	// Changed are the paths whose values differ.
+	// +optional
+	Changed []string `json:"changed,omitempty"`
+
+	// Truncated is set when paths were left out to keep the status small.
+	// +optional
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// RequestDetails describes the last HTTP request sent.
+type RequestDetails struct {
+	Method  string              `json:"method"`
+	Body    string              `json:"body,omitempty"`
+	URL     string              `json:"url"`
+	Headers map[string][]string `json:"headers,omitempty"`
+
+	// Proxy is the proxy the request was sent through, if any.
+	Proxy string `json:"proxy,omitempty"`
 }
 
 type Cache struct {
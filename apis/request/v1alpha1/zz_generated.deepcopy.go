@@ -22,10 +22,72 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Adoption) DeepCopyInto(out *Adoption) {
+	*out = *in
+	if in.Lookup != nil {
+		in, out := &in.Lookup, &out.Lookup
+		*out = new(Mapping)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Adoption.
+func (in *Adoption) DeepCopy() *Adoption {
+	if in == nil {
+		return nil
+	}
+	out := new(Adoption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AsyncOperation) DeepCopyInto(out *AsyncOperation) {
+	*out = *in
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AsyncOperation.
+func (in *AsyncOperation) DeepCopy() *AsyncOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(AsyncOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AsyncOperationStatus) DeepCopyInto(out *AsyncOperationStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AsyncOperationStatus.
+func (in *AsyncOperationStatus) DeepCopy() *AsyncOperationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AsyncOperationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cache) DeepCopyInto(out *Cache) {
 	*out = *in
@@ -42,6 +104,81 @@ func (in *Cache) DeepCopy() *Cache {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Comparison) DeepCopyInto(out *Comparison) {
+	*out = *in
+	if in.IgnorePaths != nil {
+		in, out := &in.IgnorePaths, &out.IgnorePaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ArraysAsSets != nil {
+		in, out := &in.ArraysAsSets, &out.ArraysAsSets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Comparison.
+func (in *Comparison) DeepCopy() *Comparison {
+	if in == nil {
+		return nil
+	}
+	out := new(Comparison)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DeletionVerification) DeepCopyInto(out *DeletionVerification) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DeletionVerification.
+func (in *DeletionVerification) DeepCopy() *DeletionVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(DeletionVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Drift) DeepCopyInto(out *Drift) {
+	*out = *in
+	if in.Added != nil {
+		in, out := &in.Added, &out.Added
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Removed != nil {
+		in, out := &in.Removed, &out.Removed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changed != nil {
+		in, out := &in.Changed, &out.Changed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Drift.
+func (in *Drift) DeepCopy() *Drift {
+	if in == nil {
+		return nil
+	}
+	out := new(Drift)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mapping) DeepCopyInto(out *Mapping) {
 	*out = *in
@@ -60,6 +197,21 @@ func (in *Mapping) DeepCopyInto(out *Mapping) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(apisv1alpha1.RetryPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HMACSigner != nil {
+		in, out := &in.HMACSigner, &out.HMACSigner
+		*out = new(apisv1alpha1.HMACSigner)
+		**out = **in
+	}
+	if in.AsyncOperation != nil {
+		in, out := &in.AsyncOperation, &out.AsyncOperation
+		*out = new(AsyncOperation)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Mapping.
@@ -72,6 +224,32 @@ func (in *Mapping) DeepCopy() *Mapping {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Masked) DeepCopyInto(out *Masked) {
+	*out = *in
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Cache != nil {
+		in, out := &in.Cache, &out.Cache
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.SecretRef = in.SecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Masked.
+func (in *Masked) DeepCopy() *Masked {
+	if in == nil {
+		return nil
+	}
+	out := new(Masked)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Payload) DeepCopyInto(out *Payload) {
 	*out = *in
@@ -114,6 +292,36 @@ func (in *Request) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestDetails) DeepCopyInto(out *RequestDetails) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestDetails.
+func (in *RequestDetails) DeepCopy() *RequestDetails {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestDetails)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RequestList) DeepCopyInto(out *RequestList) {
 	*out = *in
@@ -177,6 +385,36 @@ func (in *RequestParameters) DeepCopyInto(out *RequestParameters) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(apisv1alpha1.TLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatusMasking != nil {
+		in, out := &in.StatusMasking, &out.StatusMasking
+		*out = new(apisv1alpha1.StatusMasking)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConnectionDetails != nil {
+		in, out := &in.ConnectionDetails, &out.ConnectionDetails
+		*out = make([]apisv1alpha1.ConnectionDetail, len(*in))
+		copy(*out, *in)
+	}
+	if in.Comparison != nil {
+		in, out := &in.Comparison, &out.Comparison
+		*out = new(Comparison)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionVerification != nil {
+		in, out := &in.DeletionVerification, &out.DeletionVerification
+		*out = new(DeletionVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Adoption != nil {
+		in, out := &in.Adoption, &out.Adoption
+		*out = new(Adoption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestParameters.
@@ -213,6 +451,25 @@ func (in *RequestStatus) DeepCopyInto(out *RequestStatus) {
 	in.Response.DeepCopyInto(&out.Response)
 	in.Cache.DeepCopyInto(&out.Cache)
 	in.RequestDetails.DeepCopyInto(&out.RequestDetails)
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = new(Drift)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AsyncOperation != nil {
+		in, out := &in.AsyncOperation, &out.AsyncOperation
+		*out = new(AsyncOperationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeletionRequested != nil {
+		in, out := &in.DeletionRequested, &out.DeletionRequested
+		*out = (*in).DeepCopy()
+	}
+	if in.Masked != nil {
+		in, out := &in.Masked, &out.Masked
+		*out = new(Masked)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestStatus.
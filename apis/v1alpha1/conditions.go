@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TypeCircuitOpen resources have their requests short-circuited because the
+// circuit of their host is open. It is kept apart from the Ready condition,
+// which the managed reconciler sets on its own.
+const TypeCircuitOpen xpv1.ConditionType = "CircuitOpen"
+
+// Reasons a resource's circuit is or is not open.
+const (
+	ReasonCircuitOpen   xpv1.ConditionReason = "CircuitOpen"
+	ReasonCircuitClosed xpv1.ConditionReason = "CircuitClosed"
+)
+
+// CircuitOpen returns a condition that indicates the requests of the resource
+// are short-circuited because the circuit of its host is open.
+func CircuitOpen(err error) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCircuitOpen,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCircuitOpen,
+		Message:            err.Error(),
+	}
+}
+
+// CircuitClosed returns a condition that indicates the requests of the
+// resource reach its host again.
+func CircuitClosed() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeCircuitOpen,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonCircuitClosed,
+	}
+}
@@ -0,0 +1,31 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// ConnectionDetail extracts a value of a successful response into the
+// connection Secret of a resource.
+type ConnectionDetail struct {
+	// Key of the value in the connection Secret.
+	Key string `json:"key"`
+
+	// Expression is a jq expression evaluated against the response, e.g.
+	// .body.credentials.password or .headers["X-Api-Key"][0]. The response
+	// has a statusCode, headers and body, parsed when it is JSON. Strings
+	// are published as is, other values as JSON, and null values are
+	// skipped.
+	Expression string `json:"expression"`
+}
@@ -29,20 +29,599 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// TLSConfig configures the TLS settings of the HTTP requests sent through
+	// this ProviderConfig.
+	// +optional
+	TLSConfig *TLSConfig `json:"tlsConfig,omitempty"`
+
+	// ConnectionPool configures the idle connections kept open to the APIs
+	// called through this ProviderConfig.
+	// +optional
+	ConnectionPool *ConnectionPool `json:"connectionPool,omitempty"`
+
+	// Proxy configures the HTTP proxy used to reach the APIs called through
+	// this ProviderConfig.
+	// +optional
+	Proxy *ProxyConfig `json:"proxy,omitempty"`
+
+	// Retry configures how failed requests sent through this ProviderConfig
+	// are retried. It can be overridden per mapping.
+	// +optional
+	Retry *RetryPolicy `json:"retry,omitempty"`
+
+	// RateLimits limits the requests sent to each target host through this
+	// ProviderConfig, across all the resources using it. Requests exceeding a
+	// limit wait for their turn, or are requeued if they cannot be sent
+	// before the reconcile times out.
+	// +optional
+	RateLimits []HostRateLimit `json:"rateLimits,omitempty"`
+
+	// CircuitBreaker stops sending requests to a target host after repeated
+	// failures, until it recovers. Disabled if not set.
+	// +optional
+	CircuitBreaker *CircuitBreaker `json:"circuitBreaker,omitempty"`
+
+	// HMACSigner signs the requests sent through this ProviderConfig with an
+	// HMAC. It can be overridden per mapping.
+	// +optional
+	HMACSigner *HMACSigner `json:"hmacSigner,omitempty"`
+
+	// Redaction configures the values hidden from the provider logs in
+	// addition to the Authorization, Cookie, Set-Cookie, X-Api-Key and
+	// Proxy-Authorization headers, which are always hidden.
+	// +optional
+	Redaction *Redaction `json:"redaction,omitempty"`
+
+	// StatusMasking configures the values replaced with *** in the status
+	// of the resources using this ProviderConfig. The rules of a resource
+	// add to these.
+	// +optional
+	StatusMasking *StatusMasking `json:"statusMasking,omitempty"`
+}
+
+// Redaction describes the request and response values hidden from the logs.
+type Redaction struct {
+	// Headers whose values are hidden.
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+
+	// BodyPaths are jq paths of the JSON body values hidden, e.g. .password
+	// or .users[].token. Bodies that are not JSON are hidden as a whole once
+	// body paths are set.
+	// +optional
+	BodyPaths []string `json:"bodyPaths,omitempty"`
+}
+
+// StatusMasking describes the request and response values replaced with ***
+// in status.requestDetails, status.response and status.cache.
+type StatusMasking struct {
+	// Headers whose values are masked.
+	// +optional
+	Headers []string `json:"headers,omitempty"`
+
+	// BodyPaths are jq paths of the JSON body values masked, e.g. .password
+	// or .users[].token. Bodies that are not JSON are masked as a whole once
+	// body paths are set.
+	// +optional
+	BodyPaths []string `json:"bodyPaths,omitempty"`
+}
+
+// HMACSigner configures an HMAC signature sent in a request header, as
+// required by webhook-style APIs, e.g. X-Signature: sha256=<hmac(body)>.
+type HMACSigner struct {
+	// Algorithm of the HMAC.
+	// +kubebuilder:validation:Enum=SHA1;SHA256;SHA512
+	// +kubebuilder:default=SHA256
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// SecretRef selects the Secret key holding the HMAC key.
+	SecretRef xpv1.SecretKeySelector `json:"secretRef"`
+
+	// Header carrying the signature.
+	// +kubebuilder:default=X-Signature
+	// +optional
+	Header string `json:"header,omitempty"`
+
+	// Prefix prepended to the encoded signature, e.g. sha256=.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// StringToSign is a Go template rendering the signed string from the
+	// .Method, .Path, .Query, .Host, .Body, .Timestamp and .Nonce of the
+	// final request, e.g. '{{ .Timestamp }}.{{ .Body }}'.
+	// +kubebuilder:default="{{ .Body }}"
+	// +optional
+	StringToSign string `json:"stringToSign,omitempty"`
+
+	// Encoding of the signature.
+	// +kubebuilder:validation:Enum=Hex;Base64
+	// +kubebuilder:default=Hex
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// TimestampHeader is the header carrying the timestamp used in the
+	// signature, if any.
+	// +optional
+	TimestampHeader string `json:"timestampHeader,omitempty"`
+
+	// TimestampFormat of the timestamp.
+	// +kubebuilder:validation:Enum=Unix;UnixMilli;RFC3339
+	// +kubebuilder:default=Unix
+	// +optional
+	TimestampFormat string `json:"timestampFormat,omitempty"`
+
+	// NonceHeader is the header carrying the random nonce used in the
+	// signature, if any.
+	// +optional
+	NonceHeader string `json:"nonceHeader,omitempty"`
+}
+
+// CircuitBreaker configures the circuit breaker of each target host. Failed
+// requests are those failing with a network error or a 5xx response.
+type CircuitBreaker struct {
+	// FailureThreshold is the number of consecutive failed requests to a
+	// host opening its circuit. Defaults to 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+
+	// OpenDuration is how long requests to the host are short-circuited once
+	// its circuit opens. Defaults to 30s.
+	// +optional
+	OpenDuration *metav1.Duration `json:"openDuration,omitempty"`
+
+	// HalfOpenRequests is the number of probe requests let through once
+	// openDuration elapsed, all of which must succeed for the circuit to
+	// close again. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HalfOpenRequests *int `json:"halfOpenRequests,omitempty"`
+}
+
+// CircuitState is the state of a circuit that is not closed.
+type CircuitState string
+
+// Circuit states.
+const (
+	// CircuitStateOpen circuits short-circuit the requests to their host.
+	CircuitStateOpen CircuitState = "Open"
+
+	// CircuitStateHalfOpen circuits let probe requests through to their host,
+	// closing once they succeed.
+	CircuitStateHalfOpen CircuitState = "HalfOpen"
+)
+
+// TrippedHost is a target host whose circuit is open.
+type TrippedHost struct {
+	// Host whose requests are short-circuited.
+	Host string `json:"host"`
+
+	// Since is when the circuit of the host opened.
+	Since metav1.Time `json:"since"`
+
+	// State of the circuit of the host.
+	// +kubebuilder:validation:Enum=Open;HalfOpen
+	// +optional
+	State CircuitState `json:"state,omitempty"`
+}
+
+// HostRateLimit limits the requests sent to a target host.
+type HostRateLimit struct {
+	// Host the limits apply to, as host or host:port. The "*" host applies
+	// to every host without limits of its own, each host having a separate
+	// budget.
+	Host string `json:"host"`
+
+	// RequestsPerSecond is the rate at which requests are sent to the host.
+	// Unlimited if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestsPerSecond *int `json:"requestsPerSecond,omitempty"`
+
+	// Burst is the number of requests that can be sent at once above the
+	// rate. Defaults to requestsPerSecond.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Burst *int `json:"burst,omitempty"`
+
+	// MaxInFlight is the maximum number of concurrent requests to the host.
+	// Unlimited if not set.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxInFlight *int `json:"maxInFlight,omitempty"`
+}
+
+// NetworkError is a class of network errors a request can be retried on.
+// +kubebuilder:validation:Enum=Timeout;ConnectionRefused;ConnectionReset;DNS
+type NetworkError string
+
+// Network errors a request can be retried on.
+const (
+	NetworkErrorTimeout           NetworkError = "Timeout"
+	NetworkErrorConnectionRefused NetworkError = "ConnectionRefused"
+	NetworkErrorConnectionReset   NetworkError = "ConnectionReset"
+	NetworkErrorDNS               NetworkError = "DNS"
+)
+
+// RetryPolicy configures how failed requests are retried with exponential
+// backoff. Unset fields fall back to the defaults, or to the ProviderConfig
+// policy when set on a mapping.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first attempt. Set to 1 to disable retries. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttempts *int `json:"maxAttempts,omitempty"`
+
+	// BaseBackoff is the delay before the first retry, doubled on each
+	// following one. Defaults to 200ms.
+	// +optional
+	BaseBackoff *metav1.Duration `json:"baseBackoff,omitempty"`
+
+	// MaxBackoff caps the delay between two attempts. Defaults to 5s.
+	// +optional
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty"`
+
+	// Jitter randomizes each delay between zero and its computed value, so
+	// resources failing together don't retry in lockstep. Defaults to true.
+	// +optional
+	Jitter *bool `json:"jitter,omitempty"`
+
+	// RetryableMethods are the HTTP methods of the requests that are retried.
+	// Defaults to the idempotent GET, HEAD, PUT, DELETE and OPTIONS, as a
+	// retried POST or PATCH may be applied twice if the first attempt reached
+	// the server.
+	// +optional
+	RetryableMethods []string `json:"retryableMethods,omitempty"`
+
+	// RetryableStatusCodes are the response status codes a request is
+	// retried on. Defaults to 429, 502, 503 and 504.
+	// +optional
+	RetryableStatusCodes []int `json:"retryableStatusCodes,omitempty"`
+
+	// RetryableNetworkErrors are the network errors a request is retried on.
+	// Defaults to all of them.
+	// +optional
+	RetryableNetworkErrors []NetworkError `json:"retryableNetworkErrors,omitempty"`
+
+	// HonorRetryAfter waits for the delay of the Retry-After header of 429
+	// and 503 responses, when it does not exceed maxBackoff, instead of the
+	// computed backoff. Defaults to true.
+	// +optional
+	HonorRetryAfter *bool `json:"honorRetryAfter,omitempty"`
+}
+
+// ProxyConfig configures an outbound HTTP proxy.
+type ProxyConfig struct {
+	// URL of the proxy, e.g. http://proxy.internal:3128.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// CredentialsSecretRef references the Secret holding the proxy
+	// credentials under its username and password keys.
+	// +optional
+	CredentialsSecretRef *xpv1.SecretReference `json:"credentialsSecretRef,omitempty"`
+
+	// NoProxy lists the hosts reached without the proxy. Entries may be host
+	// names, domain suffixes such as .example.com, IP addresses or CIDRs.
+	// +optional
+	NoProxy []string `json:"noProxy,omitempty"`
+
+	// FromEnvironment, when true and url is not set, uses the proxy described
+	// by the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables of
+	// the provider.
+	// +optional
+	FromEnvironment bool `json:"fromEnvironment,omitempty"`
+}
+
+// ConnectionPool configures the keep-alive connections reused across
+// requests.
+type ConnectionPool struct {
+	// MaxIdleConns is the maximum number of idle connections across all
+	// hosts. Defaults to 100.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxIdleConns *int `json:"maxIdleConns,omitempty"`
+
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept per
+	// host. Defaults to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxIdleConnsPerHost *int `json:"maxIdleConnsPerHost,omitempty"`
+
+	// IdleConnTimeout is how long an idle connection is kept open. Defaults
+	// to 90s.
+	// +optional
+	IdleConnTimeout *metav1.Duration `json:"idleConnTimeout,omitempty"`
+}
+
+// TLSConfig configures how the server certificate is verified and which
+// client certificate is presented.
+type TLSConfig struct {
+	// CABundle selects the PEM encoded CA certificates used, in addition to
+	// the system ones, to verify the server certificate.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+
+	// ClientCertSecretRef selects the Secret key holding the PEM encoded
+	// client certificate.
+	// +optional
+	ClientCertSecretRef *xpv1.SecretKeySelector `json:"clientCertSecretRef,omitempty"`
+
+	// ClientKeySecretRef selects the Secret key holding the PEM encoded
+	// client private key.
+	// +optional
+	ClientKeySecretRef *xpv1.SecretKeySelector `json:"clientKeySecretRef,omitempty"`
+
+	// MinVersion is the minimum TLS version accepted.
+	// +kubebuilder:validation:Enum="1.0";"1.1";"1.2";"1.3"
+	// +optional
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// ServerName overrides the host name used to verify the server
+	// certificate.
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+}
+
+// CABundleSource selects a key of a Secret or a ConfigMap holding a CA bundle.
+type CABundleSource struct {
+	// SecretRef selects the Secret key holding the CA bundle.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// ConfigMapRef selects the ConfigMap key holding the CA bundle.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// ConfigMapKeySelector selects a key of a ConfigMap.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key whose value is selected.
+	Key string `json:"key"`
 }
 
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
-	// Source of the provider credentials.
+	// Source of the provider credentials. Secret reads the key selected by
+	// secretRef, Environment the variable named by env.name and Filesystem
+	// the file at fs.path. InjectedIdentity uses the service account token of
+	// the provider pod. Filesystem and InjectedIdentity tokens are read again
+	// when they are rotated. No credentials are sent when auth is omitted.
 	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
+
+	// Auth configures how the credentials are applied to every HTTP request
+	// sent through this ProviderConfig. When omitted, no credentials are sent.
+	// +optional
+	Auth *Auth `json:"auth,omitempty"`
+}
+
+// AuthType is the authentication scheme applied to outgoing HTTP requests.
+type AuthType string
+
+// Supported authentication schemes.
+const (
+	AuthTypeBasic  AuthType = "Basic"
+	AuthTypeBearer AuthType = "Bearer"
+	AuthTypeAPIKey AuthType = "APIKey"
+	AuthTypeOAuth2 AuthType = "OAuth2"
+	AuthTypeSigV4  AuthType = "SigV4"
+	AuthTypeJWT    AuthType = "JWT"
+)
+
+// APIKeyLocation is where an API key is placed in the HTTP request.
+type APIKeyLocation string
+
+// Supported API key locations.
+const (
+	APIKeyInHeader APIKeyLocation = "Header"
+	APIKeyInQuery  APIKeyLocation = "Query"
+)
+
+// Auth describes how the credentials of the source are used to authenticate
+// HTTP requests.
+type Auth struct {
+	// Type of authentication. Basic reads the username and password fields
+	// of the JSON credentials of the source, Bearer and APIKey send the
+	// credentials of the source. OAuth2 obtains access tokens through the
+	// client credentials flow described by the oauth2 block. SigV4 signs
+	// requests with the AWS access keys of the JSON credentials of the
+	// source. JWT mints JWTs signed with the private key of the source, as
+	// described by the jwt block.
+	// +kubebuilder:validation:Enum=Basic;Bearer;APIKey;OAuth2;SigV4;JWT
+	Type AuthType `json:"type"`
+
+	// Basic configures HTTP basic authentication.
+	// +optional
+	Basic *BasicAuth `json:"basic,omitempty"`
+
+	// APIKey configures API key authentication.
+	// +optional
+	APIKey *APIKeyAuth `json:"apiKey,omitempty"`
+
+	// OAuth2 configures the OAuth2 client credentials flow.
+	// +optional
+	OAuth2 *OAuth2Auth `json:"oauth2,omitempty"`
+
+	// SigV4 configures AWS Signature Version 4 request signing.
+	// +optional
+	SigV4 *SigV4Auth `json:"sigv4,omitempty"`
+
+	// JWT configures JWT bearer assertions minted from a private key.
+	// +optional
+	JWT *JWTAuth `json:"jwt,omitempty"`
+}
+
+// BasicAuth selects the fields of the JSON credentials that hold the basic
+// authentication credentials.
+type BasicAuth struct {
+	// UsernameKey is the field of the credentials holding the username.
+	// +kubebuilder:default=username
+	// +optional
+	UsernameKey string `json:"usernameKey,omitempty"`
+
+	// PasswordKey is the field of the credentials holding the password.
+	// +kubebuilder:default=password
+	// +optional
+	PasswordKey string `json:"passwordKey,omitempty"`
+}
+
+// APIKeyAuth configures where the API key is sent.
+type APIKeyAuth struct {
+	// In is the location of the API key in the request.
+	// +kubebuilder:validation:Enum=Header;Query
+	// +kubebuilder:default=Header
+	// +optional
+	In APIKeyLocation `json:"in,omitempty"`
+
+	// Name of the header or query parameter carrying the API key.
+	Name string `json:"name"`
+}
+
+// OAuth2Auth describes an OAuth2 client credentials flow. Access tokens are
+// cached per ProviderConfig until they are about to expire.
+type OAuth2Auth struct {
+	// TokenURL is the token endpoint of the authorization server.
+	TokenURL string `json:"tokenURL"`
+
+	// ClientIDSecretRef selects the Secret key holding the client ID.
+	ClientIDSecretRef xpv1.SecretKeySelector `json:"clientIDSecretRef"`
+
+	// ClientSecretSecretRef selects the Secret key holding the client secret.
+	ClientSecretSecretRef xpv1.SecretKeySelector `json:"clientSecretSecretRef"`
+
+	// Scopes requested for the access token.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Audience of the access token, sent as the audience parameter of the
+	// token request.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// RefreshBefore is how long before its expiry a cached token is renewed.
+	// Defaults to 1m.
+	// +optional
+	RefreshBefore *metav1.Duration `json:"refreshBefore,omitempty"`
+}
+
+// SigV4Auth configures AWS Signature Version 4 request signing. The access
+// keys are read from the JSON credentials of the source.
+type SigV4Auth struct {
+	// Region the requests are signed for, e.g. eu-west-1.
+	Region string `json:"region"`
+
+	// Service the requests are signed for, e.g. execute-api or es.
+	Service string `json:"service"`
+
+	// AccessKeyIDKey is the field of the credentials holding the access key
+	// ID.
+	// +kubebuilder:default=accessKeyId
+	// +optional
+	AccessKeyIDKey string `json:"accessKeyIdKey,omitempty"`
+
+	// SecretAccessKeyKey is the field of the credentials holding the secret
+	// access key.
+	// +kubebuilder:default=secretAccessKey
+	// +optional
+	SecretAccessKeyKey string `json:"secretAccessKeyKey,omitempty"`
+
+	// SessionTokenKey is the field of the credentials holding the session
+	// token of temporary credentials. It is optional in the credentials.
+	// +kubebuilder:default=sessionToken
+	// +optional
+	SessionTokenKey string `json:"sessionTokenKey,omitempty"`
+}
+
+// JWTExchangeStyle is how a minted JWT is exchanged for an access token.
+type JWTExchangeStyle string
+
+// Supported JWT exchange styles.
+const (
+	JWTExchangeBearerGrant JWTExchangeStyle = "JWTBearerGrant"
+	JWTExchangeBearer      JWTExchangeStyle = "Bearer"
+)
+
+// JWTAuth describes the short-lived JWTs minted from the private key of the
+// credentials source, e.g. for service accounts or GitHub Apps. JWTs are
+// cached per ProviderConfig and minted again shortly before they expire, or
+// as soon as the key or the claims change.
+type JWTAuth struct {
+	// Algorithm signing the JWTs.
+	// +kubebuilder:validation:Enum=RS256;RS384;RS512;PS256;PS384;PS512;ES256;ES384;ES512;EdDSA
+	// +kubebuilder:default=RS256
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// KeyID is sent as the kid header of the JWTs.
+	// +optional
+	KeyID string `json:"keyID,omitempty"`
+
+	// Issuer is the iss claim of the JWTs, e.g. a service account email or a
+	// GitHub App ID.
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// Subject is the sub claim of the JWTs.
+	// +optional
+	Subject string `json:"subject,omitempty"`
+
+	// Audience is the aud claim of the JWTs.
+	// +optional
+	Audience string `json:"audience,omitempty"`
+
+	// Scopes are sent space separated as the scope claim of the JWTs.
+	// +optional
+	Scopes []string `json:"scopes,omitempty"`
+
+	// Lifetime of the minted JWTs. Defaults to 5m.
+	// +optional
+	Lifetime *metav1.Duration `json:"lifetime,omitempty"`
+
+	// TokenExchange, when set, exchanges the JWTs for access tokens sent
+	// instead of the JWTs.
+	// +optional
+	TokenExchange *JWTTokenExchange `json:"tokenExchange,omitempty"`
+
+	// RefreshBefore is how long before its expiry a cached token is renewed.
+	// Defaults to 1m.
+	// +optional
+	RefreshBefore *metav1.Duration `json:"refreshBefore,omitempty"`
+}
+
+// JWTTokenExchange describes how a JWT is exchanged for an access token.
+type JWTTokenExchange struct {
+	// URL of the token endpoint.
+	URL string `json:"url"`
+
+	// Style of the exchange. JWTBearerGrant posts the JWT as an RFC 7523
+	// assertion grant. Bearer posts to the URL with the JWT as bearer token,
+	// e.g. to obtain GitHub App installation tokens.
+	// +kubebuilder:validation:Enum=JWTBearerGrant;Bearer
+	// +kubebuilder:default=JWTBearerGrant
+	// +optional
+	Style JWTExchangeStyle `json:"style,omitempty"`
 }
 
 // A ProviderConfigStatus reflects the observed state of a ProviderConfig.
 type ProviderConfigStatus struct {
 	xpv1.ProviderConfigStatus `json:",inline"`
+
+	// TrippedHosts are the target hosts whose circuit is currently open or
+	// half-open.
+	// +optional
+	TrippedHosts []TrippedHost `json:"trippedHosts,omitempty"`
 }
 
 // +kubebuilder:object:root=true
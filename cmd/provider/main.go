@@ -33,6 +33,7 @@ import (
 
 	"github.com/crossplane-contrib/provider-http/apis"
 	template "github.com/crossplane-contrib/provider-http/internal/controller"
+	"github.com/crossplane-contrib/provider-http/internal/features"
 )
 
 func main() {
@@ -45,6 +46,9 @@ func main() {
 		pollInterval     = app.Flag("poll", "How often individual resources will be checked for drift from the desired state").Default("1m").Duration()
 		maxReconcileRate = app.Flag("max-reconcile-rate", "The global maximum rate per second at which resources may checked for drift from the desired state.").Default("10").Int()
 
+		enableManagementPolicies = app.Flag("enable-management-policies", "Enable support for management policies.").Default("false").Envar("ENABLE_MANAGEMENT_POLICIES").Bool()
+		secretNamespace          = app.Flag("secret-namespace", "The only namespace whose Secrets requests may reference through placeholders.").Default("crossplane-system").Envar("SECRET_NAMESPACE").String()
+
 		// namespace = app.Flag("namespace", "Namespace used to set as default scope in default secret store config.").Default("crossplane-system").Envar("POD_NAMESPACE").String()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
@@ -88,6 +92,11 @@ func main() {
 		Features:                &feature.Flags{},
 	}
 
-	kingpin.FatalIfError(template.Setup(mgr, o, *timeout), "Cannot setup Template controllers")
+	if *enableManagementPolicies {
+		o.Features.Enable(features.EnableAlphaManagementPolicies)
+		log.Info("Alpha feature enabled", "flag", features.EnableAlphaManagementPolicies)
+	}
+
+	kingpin.FatalIfError(template.Setup(mgr, o, *timeout, *secretNamespace), "Cannot setup Template controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
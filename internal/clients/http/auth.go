@@ -0,0 +1,79 @@
+package http
+
+import (
+	"context"
+	"net/http"
+)
+
+// Authenticator applies credentials to an outgoing HTTP request.
+type Authenticator interface {
+	Authenticate(ctx context.Context, request *http.Request) error
+}
+
+// RefreshableAuthenticator is an Authenticator holding cached credentials
+// that can be dropped once the server rejects them.
+type RefreshableAuthenticator interface {
+	Authenticator
+	Invalidate()
+}
+
+type basicAuthenticator struct {
+	username string
+	password string
+}
+
+// NewBasicAuthenticator returns an Authenticator that sets HTTP basic
+// authentication on every request.
+func NewBasicAuthenticator(username, password string) Authenticator {
+	return &basicAuthenticator{username: username, password: password}
+}
+
+func (a *basicAuthenticator) Authenticate(_ context.Context, request *http.Request) error {
+	request.SetBasicAuth(a.username, a.password)
+	return nil
+}
+
+type bearerAuthenticator struct {
+	token string
+}
+
+// NewBearerAuthenticator returns an Authenticator that sends the given token
+// in the Authorization header.
+func NewBearerAuthenticator(token string) Authenticator {
+	return &bearerAuthenticator{token: token}
+}
+
+func (a *bearerAuthenticator) Authenticate(_ context.Context, request *http.Request) error {
+	request.Header.Set("Authorization", "Bearer "+a.token)
+	return nil
+}
+
+type apiKeyAuthenticator struct {
+	name    string
+	key     string
+	inQuery bool
+}
+
+// NewAPIKeyHeaderAuthenticator returns an Authenticator that sends the API key
+// in the named header.
+func NewAPIKeyHeaderAuthenticator(name, key string) Authenticator {
+	return &apiKeyAuthenticator{name: name, key: key}
+}
+
+// NewAPIKeyQueryAuthenticator returns an Authenticator that sends the API key
+// as the named query parameter.
+func NewAPIKeyQueryAuthenticator(name, key string) Authenticator {
+	return &apiKeyAuthenticator{name: name, key: key, inQuery: true}
+}
+
+func (a *apiKeyAuthenticator) Authenticate(_ context.Context, request *http.Request) error {
+	if !a.inQuery {
+		request.Header.Set(a.name, a.key)
+		return nil
+	}
+
+	query := request.URL.Query()
+	query.Set(a.name, a.key)
+	request.URL.RawQuery = query.Encode()
+	return nil
+}
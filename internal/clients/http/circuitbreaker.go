@@ -0,0 +1,310 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errCircuitOpen = "circuit open for host %s until %s"
+
+	circuitChangesBuffer = 100
+)
+
+// CircuitBreakerConfig configures the circuit breaker of each target host.
+// The breaker is disabled when FailureThreshold is zero.
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive failed requests opening
+	// the circuit.
+	FailureThreshold int
+
+	// OpenDuration is how long requests are short-circuited once the
+	// circuit opens.
+	OpenDuration time.Duration
+
+	// HalfOpenRequests is the number of probe requests that must succeed
+	// after OpenDuration for the circuit to close again.
+	HalfOpenRequests int
+}
+
+// WithCircuitBreaker sets the circuit breaker of the requests sent to each
+// target host.
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(c *client) {
+		c.circuitBreaker = config
+	}
+}
+
+// CircuitOpenError is returned when a request is short-circuited because the
+// circuit of its host is open.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf(errCircuitOpen, e.Host, e.Until.UTC().Format(time.RFC3339))
+}
+
+// IsCircuitOpen reports whether err was caused by the open circuit of a host.
+// Such requests were not sent.
+func IsCircuitOpen(err error) bool {
+	var circuitOpen *CircuitOpenError
+	return errors.As(err, &circuitOpen)
+}
+
+// TrippedHost is a host whose circuit is not closed.
+type TrippedHost struct {
+	Host  string
+	Since time.Time
+
+	// HalfOpen is true once the circuit lets probe requests through.
+	HalfOpen bool
+
+	// Until is when an open circuit starts letting probe requests through.
+	Until time.Time
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuitBreaker tracks the failures of the requests sent to a host.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	config    CircuitBreakerConfig
+	state     circuitState
+	failures  int
+	openedAt  time.Time
+	trippedAt time.Time
+	probes    int
+	passed    int
+}
+
+// allow reports whether a request can be sent, returning the error to
+// short-circuit it with otherwise.
+func (b *circuitBreaker) allow(host string, now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == circuitOpen {
+		until := b.openedAt.Add(b.config.OpenDuration)
+		if now.Before(until) {
+			return &CircuitOpenError{Host: host, Until: until}
+		}
+
+		b.state = circuitHalfOpen
+		b.probes = 0
+		b.passed = 0
+	}
+
+	if b.state == circuitHalfOpen {
+		if b.probes >= b.config.HalfOpenRequests {
+			return &CircuitOpenError{Host: host, Until: now}
+		}
+		b.probes++
+	}
+
+	return nil
+}
+
+// outcome is the result of a request as seen by the circuit breaker.
+type outcome int
+
+const (
+	requestSucceeded outcome = iota
+	requestFailed
+
+	// requestNotSent is the outcome of requests that never reached the host,
+	// telling nothing about its health.
+	requestNotSent
+)
+
+// record accounts for the outcome of a sent request, reporting whether the
+// circuit opened, opened again or closed as a result.
+func (b *circuitBreaker) record(result outcome, now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch {
+	case result == requestNotSent:
+		if b.state == circuitHalfOpen && b.probes > 0 {
+			// Let another probe through instead.
+			b.probes--
+		}
+		return false
+	case result == requestFailed && b.state == circuitHalfOpen:
+		b.state = circuitOpen
+		b.openedAt = now
+		return true
+	case result == requestFailed:
+		b.failures++
+		if b.state == circuitClosed && b.failures >= b.config.FailureThreshold {
+			b.state = circuitOpen
+			b.openedAt = now
+			b.trippedAt = now
+			return true
+		}
+		return false
+	case b.state == circuitHalfOpen:
+		b.passed++
+		if b.passed < b.config.HalfOpenRequests {
+			return false
+		}
+		b.state = circuitClosed
+		b.failures = 0
+		return true
+	default:
+		b.failures = 0
+		return false
+	}
+}
+
+// outcomeOf returns the outcome of a request for the health of its host.
+// Canceled and rate limited requests have none.
+func outcomeOf(response *http.Response, err error) outcome {
+	switch {
+	case errors.Is(err, context.Canceled) || IsRateLimited(err):
+		return requestNotSent
+	case err != nil || response.StatusCode >= http.StatusInternalServerError:
+		return requestFailed
+	default:
+		return requestSucceeded
+	}
+}
+
+// breakerRegistry holds the circuit breakers shared by all clients of the
+// provider.
+type breakerRegistry struct {
+	mu       sync.Mutex
+	breakers map[string]map[string]*circuitBreaker
+	changes  chan string
+}
+
+var defaultBreakerRegistry = newBreakerRegistry()
+
+func newBreakerRegistry() *breakerRegistry {
+	return &breakerRegistry{
+		breakers: map[string]map[string]*circuitBreaker{},
+		changes:  make(chan string, circuitChangesBuffer),
+	}
+}
+
+// get returns the breaker of the host for the given ProviderConfig, updating
+// its configuration if it changed.
+func (r *breakerRegistry) get(providerConfig, host string, config CircuitBreakerConfig) *circuitBreaker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hosts, ok := r.breakers[providerConfig]
+	if !ok {
+		hosts = map[string]*circuitBreaker{}
+		r.breakers[providerConfig] = hosts
+	}
+
+	b, ok := hosts[host]
+	if !ok {
+		b = &circuitBreaker{config: config}
+		hosts[host] = b
+	}
+
+	b.mu.Lock()
+	b.config = config
+	b.mu.Unlock()
+
+	return b
+}
+
+// evict forgets the breakers of the given ProviderConfig.
+func (r *breakerRegistry) evict(providerConfig string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.breakers, providerConfig)
+}
+
+// tripped returns the hosts of the given ProviderConfig whose circuit is not
+// closed at now, sorted by host. Open circuits whose open duration elapsed are
+// reported half-open, as the next request to their host is a probe.
+func (r *breakerRegistry) tripped(providerConfig string, now time.Time) []TrippedHost {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var result []TrippedHost
+	for host, b := range r.breakers[providerConfig] {
+		b.mu.Lock()
+		if b.state != circuitClosed {
+			until := b.openedAt.Add(b.config.OpenDuration)
+			h := TrippedHost{Host: host, Since: b.trippedAt, HalfOpen: true}
+			if b.state == circuitOpen && now.Before(until) {
+				h.HalfOpen = false
+				h.Until = until
+			}
+			result = append(result, h)
+		}
+		b.mu.Unlock()
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Host < result[j].Host
+	})
+	return result
+}
+
+// notify signals that a circuit of the given ProviderConfig opened or closed,
+// dropping the signal if nobody keeps up with them.
+func (r *breakerRegistry) notify(providerConfig string) {
+	select {
+	case r.changes <- providerConfig:
+	default:
+	}
+}
+
+// TrippedHosts returns the hosts whose circuit is currently open or half-open
+// for the given ProviderConfig.
+func TrippedHosts(providerConfig string) []TrippedHost {
+	return defaultBreakerRegistry.tripped(providerConfig, time.Now())
+}
+
+// CircuitChanges returns the channel receiving the name of a ProviderConfig
+// whenever one of its circuits opens, opens again or closes. Open circuits
+// turning half-open are not signaled, see TrippedHost.Until.
+func CircuitChanges() <-chan string {
+	return defaultBreakerRegistry.changes
+}
+
+// withCircuitBreaker sends the request through the circuit breaker of its
+// host, if enabled.
+func (hc *client) withCircuitBreaker(requestURL string, send func() (*http.Response, error)) (*http.Response, error) {
+	if hc.circuitBreaker.FailureThreshold <= 0 {
+		return send()
+	}
+
+	u, err := url.Parse(requestURL)
+	if err != nil {
+		return send()
+	}
+
+	b := hc.breakers.get(hc.providerConfig, u.Host, hc.circuitBreaker)
+	if err := b.allow(u.Host, time.Now()); err != nil {
+		return nil, err
+	}
+
+	response, err := send()
+	if b.record(outcomeOf(response, err), time.Now()) {
+		hc.breakers.notify(hc.providerConfig)
+	}
+
+	return response, err
+}
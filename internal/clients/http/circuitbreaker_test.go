@@ -0,0 +1,229 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	config := CircuitBreakerConfig{
+		FailureThreshold: 2,
+		OpenDuration:     time.Minute,
+		HalfOpenRequests: 1,
+	}
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type step struct {
+		at      time.Duration
+		outcome outcome
+		allowed bool
+		changed bool
+	}
+	cases := map[string]struct {
+		steps []step
+		want  circuitState
+	}{
+		"SuccessesKeepClosed": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestSucceeded, allowed: true},
+				{outcome: requestFailed, allowed: true},
+			},
+			want: circuitClosed,
+		},
+		"ConsecutiveFailuresOpen": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+				{at: time.Second, allowed: false},
+			},
+			want: circuitOpen,
+		},
+		"ProbeSucceedsCloses": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+				{at: 2 * time.Minute, outcome: requestSucceeded, allowed: true, changed: true},
+			},
+			want: circuitClosed,
+		},
+		"ProbeNotSentKeepsHalfOpen": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+				{at: 2 * time.Minute, outcome: requestNotSent, allowed: true},
+			},
+			want: circuitHalfOpen,
+		},
+		"ProbeNotSentLetsAnotherThrough": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+				{at: 2 * time.Minute, outcome: requestNotSent, allowed: true},
+				{at: 2*time.Minute + time.Second, outcome: requestSucceeded, allowed: true, changed: true},
+			},
+			want: circuitClosed,
+		},
+		"NotSentKeepsFailures": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestNotSent, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+			},
+			want: circuitOpen,
+		},
+		"ProbeFailsReopens": {
+			steps: []step{
+				{outcome: requestFailed, allowed: true},
+				{outcome: requestFailed, allowed: true, changed: true},
+				{at: 2 * time.Minute, outcome: requestFailed, allowed: true, changed: true},
+				{at: 2*time.Minute + time.Second, allowed: false},
+			},
+			want: circuitOpen,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b := &circuitBreaker{config: config}
+			for i, s := range tc.steps {
+				now := start.Add(s.at)
+
+				err := b.allow("api.example.com", now)
+				if diff := cmp.Diff(s.allowed, err == nil); diff != "" {
+					t.Fatalf("step %d: allow(...): -want allowed, +got allowed: %s", i, diff)
+				}
+				if err != nil {
+					continue
+				}
+
+				if diff := cmp.Diff(s.changed, b.record(s.outcome, now)); diff != "" {
+					t.Fatalf("step %d: record(...): -want changed, +got changed: %s", i, diff)
+				}
+			}
+
+			if diff := cmp.Diff(tc.want, b.state); diff != "" {
+				t.Fatalf("circuitBreaker: -want state, +got state: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestCircuitBreaker(t *testing.T) {
+	var received int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&received, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := &client{
+		log:             logging.NewNopLogger(),
+		timeout:         time.Minute,
+		retryPolicy:     RetryPolicy{MaxAttempts: 1},
+		transportConfig: DefaultTransportConfig,
+		transports:      newTransportCache(),
+		limiters:        newLimiterRegistry(),
+		breakers:        newBreakerRegistry(),
+		providerConfig:  "http-conf",
+		circuitBreaker: CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenDuration:     time.Minute,
+			HalfOpenRequests: 1,
+		},
+	}
+
+	for i := 0; i < 3; i++ {
+		_, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false)
+		if diff := cmp.Diff(i == 2, IsCircuitOpen(err)); diff != "" {
+			t.Fatalf("SendRequest(...) #%d: -want circuit open, +got circuit open: %s", i, diff)
+		}
+	}
+
+	if diff := cmp.Diff(int32(2), atomic.LoadInt32(&received)); diff != "" {
+		t.Fatalf("SendRequest(...): -want received requests, +got received requests: %s", diff)
+	}
+
+	tripped := c.breakers.tripped("http-conf", time.Now())
+	if diff := cmp.Diff(1, len(tripped)); diff != "" {
+		t.Fatalf("tripped(...): -want tripped hosts, +got tripped hosts: %s", diff)
+	}
+
+	if diff := cmp.Diff(false, tripped[0].HalfOpen); diff != "" {
+		t.Fatalf("tripped(...): -want half-open, +got half-open: %s", diff)
+	}
+
+	// Once the open duration elapsed, the next request to the host is a probe.
+	tripped = c.breakers.tripped("http-conf", tripped[0].Until)
+	if diff := cmp.Diff(true, tripped[0].HalfOpen); diff != "" {
+		t.Fatalf("tripped(...): -want half-open, +got half-open: %s", diff)
+	}
+
+	select {
+	case name := <-c.breakers.changes:
+		if diff := cmp.Diff("http-conf", name); diff != "" {
+			t.Fatalf("changes: -want ProviderConfig, +got ProviderConfig: %s", diff)
+		}
+	default:
+		t.Fatalf("changes: expected a circuit change to be notified")
+	}
+}
+
+func Test_breakerRegistryEvict(t *testing.T) {
+	r := newBreakerRegistry()
+	config := CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute, HalfOpenRequests: 1}
+	r.get("http-conf", "api.example.com", config).record(requestFailed, time.Now())
+	r.get("http-conf-other", "api.example.com", config).record(requestFailed, time.Now())
+
+	r.evict("http-conf")
+
+	if diff := cmp.Diff(0, len(r.tripped("http-conf", time.Now()))); diff != "" {
+		t.Fatalf("evict(...): -want tripped hosts, +got tripped hosts: %s", diff)
+	}
+	if diff := cmp.Diff(1, len(r.tripped("http-conf-other", time.Now()))); diff != "" {
+		t.Fatalf("evict(...): -want tripped hosts, +got tripped hosts: %s", diff)
+	}
+}
+
+func Test_outcomeOf(t *testing.T) {
+	cases := map[string]struct {
+		response *http.Response
+		err      error
+		want     outcome
+	}{
+		"Success": {
+			response: &http.Response{StatusCode: http.StatusNotFound},
+			want:     requestSucceeded,
+		},
+		"ServerError": {
+			response: &http.Response{StatusCode: http.StatusBadGateway},
+			want:     requestFailed,
+		},
+		"NetworkError": {
+			err:  errors.New("connection refused"),
+			want: requestFailed,
+		},
+		"Canceled": {
+			err:  context.Canceled,
+			want: requestNotSent,
+		},
+		"RateLimited": {
+			err:  &RateLimitedError{Host: "api.example.com", Err: context.DeadlineExceeded},
+			want: requestNotSent,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, outcomeOf(tc.response, tc.err)); diff != "" {
+				t.Fatalf("outcomeOf(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
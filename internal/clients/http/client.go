@@ -3,7 +3,6 @@ package http
 import (
 	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +10,13 @@ import (
 	"time"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	errAuthenticateRequest = "cannot authenticate request"
+	errBuildRequest        = "cannot build %s request to %s"
 )
 
 // Client is the interface to interact with Http
@@ -19,8 +25,36 @@ type Client interface {
 }
 
 type client struct {
-	log     logging.Logger
-	timeout time.Duration
+	log           logging.Logger
+	timeout       time.Duration
+	authenticator Authenticator
+	signer        Signer
+	tlsConfigData TLSConfigData
+	proxyConfig   ProxyConfig
+	retryPolicy   RetryPolicy
+	rateLimits    []HostRateLimit
+	limiters      *limiterRegistry
+
+	circuitBreaker CircuitBreakerConfig
+	breakers       *breakerRegistry
+
+	redaction RedactionConfig
+	redactor  *Redactor
+
+	transportConfig TransportConfig
+	transports      *transportCache
+	providerConfig  string
+	generation      int64
+}
+
+// Option configures the Http client.
+type Option func(*client)
+
+// WithAuthenticator sets the Authenticator applied to every request.
+func WithAuthenticator(a Authenticator) Option {
+	return func(c *client) {
+		c.authenticator = a
+	}
 }
 
 type HttpResponse struct {
@@ -34,6 +68,7 @@ type HttpRequest struct {
 	Body    string              `json:"body,omitempty"`
 	URL     string              `json:"url"`
 	Headers map[string][]string `json:"headers,omitempty"`
+	Proxy   string              `json:"proxy,omitempty"`
 }
 
 type HttpDetails struct {
@@ -42,42 +77,24 @@ type HttpDetails struct {
 }
 
 func (hc *client) SendRequest(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (details HttpDetails, err error) {
-	requestBody := []byte(body)
-	request, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(requestBody))
-
 	requestDetails := HttpRequest{
 		URL:     url,
 		Body:    body,
 		Headers: headers,
 		Method:  method,
+		Proxy:   hc.proxyFor(url),
 	}
 
+	response, err := hc.withCircuitBreaker(url, func() (*http.Response, error) {
+		return hc.send(ctx, requestDetails, skipTLSVerify)
+	})
 	if err != nil {
 		return HttpDetails{
 			HttpRequest: requestDetails,
 		}, err
 	}
-
-	for key, values := range headers {
-		for _, value := range values {
-			request.Header.Add(key, value)
-		}
-	}
-
-	client := &http.Client{
-		Transport: &http.Transport{
-			// #nosec G402
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: skipTLSVerify},
-		},
-		Timeout: hc.timeout,
-	}
-
-	response, err := client.Do(request)
-	if err != nil {
-		return HttpDetails{
-			HttpRequest: requestDetails,
-		}, err
-	}
+	// Closing the body releases the rate limits the request holds.
+	defer response.Body.Close() //nolint:errcheck
 
 	responsebody, err := io.ReadAll(response.Body)
 	if err != nil {
@@ -92,14 +109,7 @@ func (hc *client) SendRequest(ctx context.Context, method string, url string, bo
 		StatusCode: response.StatusCode,
 	}
 
-	err = response.Body.Close()
-	if err != nil {
-		return HttpDetails{
-			HttpRequest: requestDetails,
-		}, err
-	}
-
-	hc.log.Info(fmt.Sprint("http request sent: ", toJSON(requestDetails)))
+	hc.log.Info(fmt.Sprint("http request sent: ", toJSON(hc.redactor.Request(requestDetails))))
 
 	return HttpDetails{
 		HttpResponse: beautifiedResponse,
@@ -107,12 +117,145 @@ func (hc *client) SendRequest(ctx context.Context, method string, url string, bo
 	}, nil
 }
 
+// send sends the request, retrying it with backoff as described by the retry
+// policy of ctx.
+func (hc *client) send(ctx context.Context, requestDetails HttpRequest, skipTLSVerify bool) (*http.Response, error) {
+	policy := hc.retryPolicyFor(ctx)
+
+	for attempt := 1; ; attempt++ {
+		response, err := hc.sendAuthenticated(ctx, requestDetails, skipTLSVerify)
+		if attempt >= policy.MaxAttempts || !policy.shouldRetry(requestDetails.Method, response, err) {
+			return response, err
+		}
+
+		delay, ok := policy.backoff(attempt, response)
+		if !ok {
+			return response, err
+		}
+
+		if response != nil {
+			_, _ = io.Copy(io.Discard, response.Body)
+			if err := response.Body.Close(); err != nil {
+				return nil, err
+			}
+		}
+
+		hc.log.Debug("retrying http request", "method", requestDetails.Method, "url", hc.redactor.URL(requestDetails.URL), "attempt", attempt, "delay", delay.String())
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// sendAuthenticated sends the request once, retrying it with fresh
+// credentials if the cached ones are rejected.
+func (hc *client) sendAuthenticated(ctx context.Context, requestDetails HttpRequest, skipTLSVerify bool) (*http.Response, error) {
+	response, err := hc.do(ctx, requestDetails, skipTLSVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	// Cached credentials may have been revoked or rotated before they expired,
+	// so drop them and retry once with fresh ones.
+	if refreshable, ok := hc.authenticator.(RefreshableAuthenticator); ok && response.StatusCode == http.StatusUnauthorized {
+		if err := response.Body.Close(); err != nil {
+			return nil, err
+		}
+
+		refreshable.Invalidate()
+		return hc.do(ctx, requestDetails, skipTLSVerify)
+	}
+
+	return response, nil
+}
+
+// do builds the HTTP request described by template, once interpolated,
+// authenticates it and sends it.
+func (hc *client) do(ctx context.Context, template HttpRequest, skipTLSVerify bool) (*http.Response, error) {
+	requestDetails := interpolate(ctx, template)
+
+	request, err := http.NewRequestWithContext(ctx, requestDetails.Method, requestDetails.URL, bytes.NewBuffer([]byte(requestDetails.Body)))
+	if err != nil {
+		return nil, errors.Errorf(errBuildRequest, template.Method, template.URL)
+	}
+
+	for key, values := range requestDetails.Headers {
+		for _, value := range values {
+			request.Header.Add(key, value)
+		}
+	}
+
+	transport, err := hc.transports.get(hc.providerConfig, hc.generation, transportSettings{
+		TLSConfigData:   hc.tlsConfigData,
+		SkipTLSVerify:   skipTLSVerify,
+		TransportConfig: hc.transportConfig,
+		ProxyConfig:     hc.proxyConfig,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   hc.timeout,
+	}
+
+	// Authenticated and signed once sent within the limits, so signatures
+	// and their timestamps don't age while the request waits.
+	release, err := hc.waitForLimits(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	if hc.authenticator != nil {
+		// Token endpoints are reached like the API, through the TLS and
+		// proxy settings of the ProviderConfig.
+		if err := hc.authenticator.Authenticate(context.WithValue(ctx, oauth2.HTTPClient, client), request); err != nil {
+			release()
+			return nil, errors.Wrap(err, errAuthenticateRequest)
+		}
+	}
+
+	if signer := hc.signerFor(ctx); signer != nil {
+		if err := signer.Sign(request); err != nil {
+			release()
+			return nil, errors.Wrap(err, errSignRequest)
+		}
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		release()
+		return nil, maskURLError(err, template.URL)
+	}
+
+	response.Body = &releaseOnClose{ReadCloser: response.Body, release: release}
+	return response, nil
+}
+
 // NewClient returns a new Http Client
-func NewClient(log logging.Logger, timeout time.Duration) (Client, error) {
-	return &client{
-		log:     log,
-		timeout: timeout,
-	}, nil
+func NewClient(log logging.Logger, timeout time.Duration, opts ...Option) (Client, error) {
+	c := &client{
+		log:             log,
+		timeout:         timeout,
+		retryPolicy:     DefaultRetryPolicy,
+		transportConfig: DefaultTransportConfig,
+		transports:      defaultTransportCache,
+		limiters:        defaultLimiterRegistry,
+		breakers:        defaultBreakerRegistry,
+	}
+
+	for _, o := range opts {
+		o(c)
+	}
+
+	redactor, err := NewRedactor(c.redaction)
+	if err != nil {
+		return nil, err
+	}
+	c.redactor = redactor
+
+	return c, nil
 }
 
 func toJSON(request HttpRequest) string {
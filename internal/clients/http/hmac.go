@@ -0,0 +1,247 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- required by some webhook APIs.
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"text/template"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseStringToSign   = "cannot parse string to sign template"
+	errRenderStringToSign  = "cannot render string to sign"
+	errUnknownHMACAlgo     = "unknown HMAC algorithm: %s"
+	errUnknownHMACEncoding = "unknown HMAC signature encoding: %s"
+	errUnknownTimeFormat   = "unknown timestamp format: %s"
+	errGenerateNonce       = "cannot generate nonce"
+	errSignRequest         = "cannot sign request"
+
+	defaultSignatureHeader = "X-Signature"
+	defaultStringToSign    = "{{ .Body }}"
+)
+
+// HMAC algorithms, signature encodings and timestamp formats.
+const (
+	HMACSHA1   = "SHA1"
+	HMACSHA256 = "SHA256"
+	HMACSHA512 = "SHA512"
+
+	EncodingHex    = "Hex"
+	EncodingBase64 = "Base64"
+
+	TimestampUnix      = "Unix"
+	TimestampUnixMilli = "UnixMilli"
+	TimestampRFC3339   = "RFC3339"
+)
+
+// Signer adds a signature to an outgoing HTTP request once it is final.
+type Signer interface {
+	Sign(request *http.Request) error
+}
+
+// WithSigner sets the Signer applied to every request.
+func WithSigner(s Signer) Option {
+	return func(c *client) {
+		c.signer = s
+	}
+}
+
+type signerKey struct{}
+
+// ContextWithSigner returns a context whose requests are signed by s instead
+// of the Signer of the client, e.g. to apply the signer of a single mapping.
+func ContextWithSigner(ctx context.Context, s Signer) context.Context {
+	if s == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, signerKey{}, s)
+}
+
+// signerFor returns the Signer of the requests sent with ctx.
+func (hc *client) signerFor(ctx context.Context) Signer {
+	if s, ok := ctx.Value(signerKey{}).(Signer); ok {
+		return s
+	}
+	return hc.signer
+}
+
+// HMACConfig describes an HMAC signature sent in a request header.
+type HMACConfig struct {
+	Algorithm string
+	Secret    []byte
+
+	// Header carrying the signature, prefixed by Prefix.
+	Header string
+	Prefix string
+
+	// StringToSign is a text/template rendering the signed string from the
+	// Method, Path, Query, Host, Body, Timestamp and Nonce of the request.
+	StringToSign string
+	Encoding     string
+
+	// TimestampHeader and NonceHeader, when set, carry the timestamp and
+	// nonce used in the signature.
+	TimestampHeader string
+	TimestampFormat string
+	NonceHeader     string
+}
+
+// StringToSignData is the data the string to sign template is rendered with.
+type StringToSignData struct {
+	Method    string
+	Path      string
+	Query     string
+	Host      string
+	Body      string
+	Timestamp string
+	Nonce     string
+}
+
+type hmacSigner struct {
+	config   HMACConfig
+	hash     func() hash.Hash
+	template *template.Template
+	now      func() time.Time
+}
+
+// NewHMACSigner returns a Signer computing an HMAC over the string rendered
+// from the request.
+func NewHMACSigner(config HMACConfig) (Signer, error) {
+	s := &hmacSigner{config: config, now: time.Now}
+
+	switch config.Algorithm {
+	case HMACSHA1:
+		s.hash = sha1.New
+	case HMACSHA256, "":
+		s.hash = sha256.New
+	case HMACSHA512:
+		s.hash = sha512.New
+	default:
+		return nil, errors.Errorf(errUnknownHMACAlgo, config.Algorithm)
+	}
+
+	switch config.Encoding {
+	case EncodingHex, EncodingBase64, "":
+	default:
+		return nil, errors.Errorf(errUnknownHMACEncoding, config.Encoding)
+	}
+
+	switch config.TimestampFormat {
+	case TimestampUnix, TimestampUnixMilli, TimestampRFC3339, "":
+	default:
+		return nil, errors.Errorf(errUnknownTimeFormat, config.TimestampFormat)
+	}
+
+	stringToSign := config.StringToSign
+	if stringToSign == "" {
+		stringToSign = defaultStringToSign
+	}
+
+	t, err := template.New("stringToSign").Option("missingkey=error").Parse(stringToSign)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseStringToSign)
+	}
+	s.template = t
+
+	return s, nil
+}
+
+func (s *hmacSigner) Sign(request *http.Request) error {
+	body, err := readBody(request)
+	if err != nil {
+		return errors.Wrap(err, errReadBody)
+	}
+
+	nonce, err := newNonce()
+	if err != nil {
+		return errors.Wrap(err, errGenerateNonce)
+	}
+
+	data := StringToSignData{
+		Method:    request.Method,
+		Path:      request.URL.EscapedPath(),
+		Query:     request.URL.RawQuery,
+		Host:      request.URL.Host,
+		Body:      string(body),
+		Timestamp: s.timestamp(),
+		Nonce:     nonce,
+	}
+
+	var stringToSign bytes.Buffer
+	if err := s.template.Execute(&stringToSign, data); err != nil {
+		return errors.Wrap(err, errRenderStringToSign)
+	}
+
+	mac := hmac.New(s.hash, s.config.Secret)
+	mac.Write(stringToSign.Bytes())
+
+	header := s.config.Header
+	if header == "" {
+		header = defaultSignatureHeader
+	}
+
+	request.Header.Set(header, s.config.Prefix+s.encode(mac.Sum(nil)))
+	if s.config.TimestampHeader != "" {
+		request.Header.Set(s.config.TimestampHeader, data.Timestamp)
+	}
+	if s.config.NonceHeader != "" {
+		request.Header.Set(s.config.NonceHeader, data.Nonce)
+	}
+
+	return nil
+}
+
+func (s *hmacSigner) timestamp() string {
+	now := s.now()
+	switch s.config.TimestampFormat {
+	case TimestampUnixMilli:
+		return strconv.FormatInt(now.UnixMilli(), 10)
+	case TimestampRFC3339:
+		return now.UTC().Format(time.RFC3339)
+	default:
+		return strconv.FormatInt(now.Unix(), 10)
+	}
+}
+
+func (s *hmacSigner) encode(signature []byte) string {
+	if s.config.Encoding == EncodingBase64 {
+		return base64.StdEncoding.EncodeToString(signature)
+	}
+	return hex.EncodeToString(signature)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// readBody returns the request body, leaving it readable.
+func readBody(request *http.Request) ([]byte, error) {
+	if request.GetBody == nil {
+		return nil, nil
+	}
+
+	body, err := request.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close() //nolint:errcheck
+
+	return io.ReadAll(body)
+}
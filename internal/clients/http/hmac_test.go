@@ -0,0 +1,225 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+var testHMACSecret = []byte("webhook-secret")
+
+func Test_hmacSigner(t *testing.T) {
+	now := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+	body := `{"name":"john_doe"}`
+
+	sha512Mac := hmac.New(sha512.New, testHMACSecret)
+	sha512Mac.Write([]byte(body))
+
+	type args struct {
+		config HMACConfig
+	}
+	type want struct {
+		headers map[string]string
+		err     error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Defaults": {
+			args: args{
+				config: HMACConfig{Secret: testHMACSecret},
+			},
+			want: want{
+				headers: map[string]string{
+					"X-Signature": hex.EncodeToString(hmacSHA256(testHMACSecret, body)),
+				},
+			},
+		},
+		"PrefixAndBase64": {
+			args: args{
+				config: HMACConfig{
+					Algorithm: HMACSHA512,
+					Secret:    testHMACSecret,
+					Header:    "X-Hub-Signature",
+					Prefix:    "sha512=",
+					Encoding:  EncodingBase64,
+				},
+			},
+			want: want{
+				headers: map[string]string{
+					"X-Hub-Signature": "sha512=" + base64.StdEncoding.EncodeToString(sha512Mac.Sum(nil)),
+				},
+			},
+		},
+		"TimestampedTemplate": {
+			args: args{
+				config: HMACConfig{
+					Algorithm:       HMACSHA256,
+					Secret:          testHMACSecret,
+					StringToSign:    "{{ .Method }}\n{{ .Path }}\n{{ .Query }}\n{{ .Timestamp }}\n{{ .Body }}",
+					TimestampHeader: "X-Timestamp",
+					TimestampFormat: TimestampRFC3339,
+				},
+			},
+			want: want{
+				headers: map[string]string{
+					"X-Signature": hex.EncodeToString(hmacSHA256(testHMACSecret,
+						"POST\n/v1/users\ndry=true\n2023-10-01T12:00:00Z\n"+body)),
+					"X-Timestamp": "2023-10-01T12:00:00Z",
+				},
+			},
+		},
+		"UnixMilliTimestamp": {
+			args: args{
+				config: HMACConfig{
+					Secret:          testHMACSecret,
+					StringToSign:    "{{ .Timestamp }}.{{ .Body }}",
+					TimestampHeader: "X-Timestamp",
+					TimestampFormat: TimestampUnixMilli,
+				},
+			},
+			want: want{
+				headers: map[string]string{
+					"X-Signature": hex.EncodeToString(hmacSHA256(testHMACSecret, "1696161600000."+body)),
+					"X-Timestamp": "1696161600000",
+				},
+			},
+		},
+		"UnknownAlgorithm": {
+			args: args{
+				config: HMACConfig{Algorithm: "MD5", Secret: testHMACSecret},
+			},
+			want: want{
+				err: errors.Errorf(errUnknownHMACAlgo, "MD5"),
+			},
+		},
+		"UnknownEncoding": {
+			args: args{
+				config: HMACConfig{Secret: testHMACSecret, Encoding: "Base32"},
+			},
+			want: want{
+				err: errors.Errorf(errUnknownHMACEncoding, "Base32"),
+			},
+		},
+		"InvalidTemplate": {
+			args: args{
+				config: HMACConfig{Secret: testHMACSecret, StringToSign: "{{ .Body "},
+			},
+			want: want{
+				err: errors.Wrap(errors.New(`template: stringToSign:1: unclosed action`), errParseStringToSign),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			signer, err := NewHMACSigner(tc.args.config)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("NewHMACSigner(...): -want error, +got error: %s", diff)
+			}
+
+			if err != nil {
+				return
+			}
+			signer.(*hmacSigner).now = func() time.Time { return now }
+
+			request, _ := http.NewRequest(http.MethodPost, "https://api.example.com/v1/users?dry=true", strings.NewReader(body))
+			if err := signer.Sign(request); err != nil {
+				t.Fatalf("Sign(...): unexpected error: %s", err)
+			}
+
+			got := map[string]string{}
+			for header := range tc.want.headers {
+				got[header] = request.Header.Get(header)
+			}
+
+			if diff := cmp.Diff(tc.want.headers, got); diff != "" {
+				t.Fatalf("Sign(...): -want headers, +got headers: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_hmacSignerNonce(t *testing.T) {
+	signer, err := NewHMACSigner(HMACConfig{
+		Secret:       testHMACSecret,
+		StringToSign: "{{ .Nonce }}",
+		NonceHeader:  "X-Nonce",
+	})
+	if err != nil {
+		t.Fatalf("NewHMACSigner(...): unexpected error: %s", err)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	if err := signer.Sign(request); err != nil {
+		t.Fatalf("Sign(...): unexpected error: %s", err)
+	}
+
+	nonce := request.Header.Get("X-Nonce")
+	want := hex.EncodeToString(hmacSHA256(testHMACSecret, nonce))
+	if diff := cmp.Diff(want, request.Header.Get("X-Signature")); diff != "" {
+		t.Fatalf("Sign(...): -want signature, +got signature: %s", diff)
+	}
+}
+
+// Test_SendRequestSignsWithinLimits checks that requests held back by the
+// rate limits are signed once sent, not before waiting.
+func Test_SendRequestSignsWithinLimits(t *testing.T) {
+	var timestamp, received int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.StoreInt64(&received, time.Now().UnixMilli())
+		ts, _ := strconv.ParseInt(r.Header.Get("X-Timestamp"), 10, 64)
+		atomic.StoreInt64(&timestamp, ts)
+	}))
+	defer server.Close()
+
+	signer, err := NewHMACSigner(HMACConfig{
+		Secret:          testHMACSecret,
+		TimestampHeader: "X-Timestamp",
+		TimestampFormat: TimestampUnixMilli,
+	})
+	if err != nil {
+		t.Fatalf("NewHMACSigner(...): unexpected error: %s", err)
+	}
+
+	c := &client{
+		log:             logging.NewNopLogger(),
+		timeout:         time.Minute,
+		retryPolicy:     RetryPolicy{MaxAttempts: 1},
+		transportConfig: DefaultTransportConfig,
+		transports:      newTransportCache(),
+		limiters:        newLimiterRegistry(),
+		rateLimits:      []HostRateLimit{{Host: anyHost, RequestsPerSecond: 2, Burst: 1}},
+		providerConfig:  "http-conf",
+		signer:          signer,
+	}
+
+	// The second request waits for the limiter.
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false); err != nil {
+			t.Fatalf("SendRequest(...) #%d: unexpected error: %s", i, err)
+		}
+	}
+	if waited := time.Since(start); waited < 400*time.Millisecond {
+		t.Fatalf("SendRequest(...): want the second request held back, sent after %s", waited)
+	}
+
+	if age := time.Duration(atomic.LoadInt64(&received)-atomic.LoadInt64(&timestamp)) * time.Millisecond; age > 200*time.Millisecond {
+		t.Fatalf("SendRequest(...): want the request signed once sent, signature %s old", age)
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+// Interpolator replaces the placeholders of a request with the values they
+// stand for, e.g. Secret values. Placeholders are only replaced in the
+// request sent, so the values never reach the logs or the returned
+// HttpDetails.
+type Interpolator interface {
+	// Interpolate replaces the placeholders of a header value or a body that
+	// is not JSON.
+	Interpolate(s string) string
+
+	// InterpolateURL replaces the placeholders of a URL, escaping the values
+	// as path segments or query components depending on where they sit.
+	InterpolateURL(s string) string
+
+	// InterpolateJSON replaces the placeholders of a JSON document, escaping
+	// the values as JSON string content.
+	InterpolateJSON(s string) string
+}
+
+type interpolatorKey struct{}
+
+// ContextWithInterpolator returns a context whose requests are interpolated
+// by i right before they are sent.
+func ContextWithInterpolator(ctx context.Context, i Interpolator) context.Context {
+	if i == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, interpolatorKey{}, i)
+}
+
+// interpolate returns requestDetails with the placeholders of ctx replaced.
+func interpolate(ctx context.Context, requestDetails HttpRequest) HttpRequest {
+	i, ok := ctx.Value(interpolatorKey{}).(Interpolator)
+	if !ok {
+		return requestDetails
+	}
+
+	requestDetails.URL = i.InterpolateURL(requestDetails.URL)
+
+	requestDetails.Body = InterpolateBody(i, requestDetails.Body)
+
+	if requestDetails.Headers != nil {
+		headers := make(map[string][]string, len(requestDetails.Headers))
+		for name, values := range requestDetails.Headers {
+			headers[name] = make([]string, len(values))
+			for j, value := range values {
+				headers[name][j] = i.Interpolate(value)
+			}
+		}
+		requestDetails.Headers = headers
+	}
+
+	return requestDetails
+}
+
+// InterpolateBody returns body with the placeholders of i replaced, escaped
+// as JSON string content when body is JSON.
+func InterpolateBody(i Interpolator, body string) string {
+	if json.Valid([]byte(body)) {
+		return i.InterpolateJSON(body)
+	}
+	return i.Interpolate(body)
+}
+
+// maskURLError replaces the interpolated URL reported by err with the
+// original one, so the interpolated values are not leaked through errors.
+func maskURLError(err error, rawURL string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = rawURL
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testInterpolator replaces placeholders with the values of its map.
+type testInterpolator map[string]string
+
+func (i testInterpolator) Interpolate(s string) string {
+	for placeholder, value := range i {
+		s = strings.ReplaceAll(s, placeholder, value)
+	}
+	return s
+}
+
+func (i testInterpolator) InterpolateURL(s string) string {
+	for placeholder, value := range i {
+		s = strings.ReplaceAll(s, placeholder, url.PathEscape(value))
+	}
+	return s
+}
+
+func (i testInterpolator) InterpolateJSON(s string) string {
+	for placeholder, value := range i {
+		b, _ := json.Marshal(value)
+		s = strings.ReplaceAll(s, placeholder, string(b[1:len(b)-1]))
+	}
+	return s
+}
+
+// Test_SendRequestInterpolates proves that the server receives the
+// interpolated request while the logs and the returned details keep the
+// placeholders.
+func Test_SendRequestInterpolates(t *testing.T) {
+	interpolator := testInterpolator{"{{token}}": "t0k3n", "{{password}}": `s3"cr3t`, "{{id}}": "1/23"}
+
+	var received HttpRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = HttpRequest{
+			URL:     r.URL.String(),
+			Body:    string(body),
+			Headers: map[string][]string{"X-Token": r.Header.Values("X-Token")},
+		}
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer server.Close()
+
+	log := newRecordingLogger()
+	c, err := NewClient(log, time.Minute)
+	if err != nil {
+		t.Fatalf("NewClient(...): unexpected error: %s", err)
+	}
+
+	rawURL := server.URL + "/users/{{id}}"
+	body := `{"password":"{{password}}"}`
+	headers := map[string][]string{"X-Token": {"{{token}}"}}
+
+	ctx := ContextWithInterpolator(context.Background(), interpolator)
+	details, err := c.SendRequest(ctx, http.MethodPost, rawURL, body, headers, false)
+	if err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	want := HttpRequest{
+		URL:     "/users/1%2F23",
+		Body:    `{"password":"s3\"cr3t"}`,
+		Headers: map[string][]string{"X-Token": {"t0k3n"}},
+	}
+	if diff := cmp.Diff(want, received); diff != "" {
+		t.Fatalf("SendRequest(...): -want received request, +got received request: %s", diff)
+	}
+
+	if details.HttpRequest.URL != rawURL || details.HttpRequest.Body != body || details.HttpRequest.Headers["X-Token"][0] != "{{token}}" {
+		t.Fatalf("SendRequest(...): returned request details were interpolated: %+v", details.HttpRequest)
+	}
+
+	logs := log.String()
+	for _, value := range []string{"t0k3n", "s3", "/users/1"} {
+		if strings.Contains(logs, value) {
+			t.Errorf("SendRequest(...): interpolated value %q reached the logs: %s", value, logs)
+		}
+	}
+}
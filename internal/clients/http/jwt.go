@@ -0,0 +1,336 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+)
+
+const (
+	errParseJWTKey      = "cannot parse JWT private key"
+	errUnknownJWTAlgo   = "unknown JWT signing algorithm: %s"
+	errUnknownExchange  = "unknown JWT token exchange style: %s"
+	errMintJWT          = "cannot mint JWT"
+	errExchangeJWT      = "cannot exchange JWT for an access token"
+	errExchangeStatus   = "token exchange returned status %d"
+	errExchangeRejected = "token exchange returned status %d: %s"
+	errExchangeResponse = "cannot decode token exchange response"
+	errExchangeNoToken  = "token exchange response has no access token"
+
+	defaultJWTLifetime = 5 * time.Minute
+
+	// defaultExchangeTimeout bounds token exchanges made without the HTTP
+	// client of a ProviderConfig.
+	defaultExchangeTimeout = 30 * time.Second
+
+	// jwtClockSkew backdates the issued at claim of minted JWTs, so servers
+	// whose clock is slightly behind accept them.
+	jwtClockSkew = 30 * time.Second
+
+	grantTypeJWTBearer = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+// JWT token exchange styles.
+const (
+	// JWTExchangeGrant posts the JWT as an RFC 7523 assertion grant, as
+	// Google-style service accounts expect.
+	JWTExchangeGrant = "JWTBearerGrant"
+
+	// JWTExchangeBearer posts to the exchange URL with the JWT as bearer
+	// token, as GitHub App installation tokens expect.
+	JWTExchangeBearer = "Bearer"
+)
+
+// JWTConfig describes a JWT signed with a private key, either sent as bearer
+// token or exchanged for an access token.
+type JWTConfig struct {
+	// Algorithm is the JWS signing algorithm, e.g. RS256 or ES256.
+	Algorithm string
+
+	// PrivateKey is the PEM encoded signing key.
+	PrivateKey []byte
+	KeyID      string
+
+	Issuer   string
+	Subject  string
+	Audience string
+	Scopes   []string
+
+	// Lifetime of the minted JWTs.
+	Lifetime time.Duration
+
+	// TokenURL, when set, is where the JWT is exchanged for an access token
+	// following ExchangeStyle.
+	TokenURL      string
+	ExchangeStyle string
+
+	// RefreshBefore is how long before its expiry a cached token is renewed.
+	RefreshBefore time.Duration
+}
+
+// key identifies the token issued for this configuration.
+func (c JWTConfig) key() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		c.Algorithm, string(c.PrivateKey), c.KeyID, c.Issuer, c.Subject, c.Audience,
+		strings.Join(c.Scopes, " "), c.Lifetime.String(), c.TokenURL, c.ExchangeStyle,
+	}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+type jwtAuthenticator struct {
+	name       string
+	key        string
+	config     JWTConfig
+	method     jwt.SigningMethod
+	signingKey interface{}
+	cache      *tokenCache
+	now        func() time.Time
+}
+
+// NewJWTAuthenticator returns an Authenticator that mints JWTs signed with
+// the configured private key and sends them, or the access token they are
+// exchanged for, as bearer token. Tokens are cached per name, typically the
+// ProviderConfig name, and renewed shortly before they expire. Changing the
+// key or the claims mints a new token right away.
+func NewJWTAuthenticator(name string, config JWTConfig) (RefreshableAuthenticator, error) {
+	if config.Lifetime == 0 {
+		config.Lifetime = defaultJWTLifetime
+	}
+	if config.RefreshBefore == 0 {
+		config.RefreshBefore = defaultRefreshBefore
+	}
+
+	switch config.ExchangeStyle {
+	case JWTExchangeGrant, JWTExchangeBearer, "":
+	default:
+		return nil, errors.Errorf(errUnknownExchange, config.ExchangeStyle)
+	}
+
+	method := jwt.GetSigningMethod(config.Algorithm)
+	if method == nil || method == jwt.SigningMethodNone {
+		return nil, errors.Errorf(errUnknownJWTAlgo, config.Algorithm)
+	}
+
+	signingKey, err := parseJWTKey(method, config.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtAuthenticator{
+		name:       name,
+		key:        config.key(),
+		config:     config,
+		method:     method,
+		signingKey: signingKey,
+		cache:      defaultTokenCache,
+		now:        time.Now,
+	}, nil
+}
+
+// parseJWTKey parses the PEM encoded private key used by method. Methods
+// signing with a shared secret are not supported.
+func parseJWTKey(method jwt.SigningMethod, key []byte) (interface{}, error) {
+	var signingKey interface{}
+	var err error
+
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		signingKey, err = jwt.ParseRSAPrivateKeyFromPEM(key)
+	case *jwt.SigningMethodECDSA:
+		signingKey, err = jwt.ParseECPrivateKeyFromPEM(key)
+	case *jwt.SigningMethodEd25519:
+		signingKey, err = jwt.ParseEdPrivateKeyFromPEM(key)
+	default:
+		return nil, errors.Errorf(errUnknownJWTAlgo, method.Alg())
+	}
+
+	return signingKey, errors.Wrap(err, errParseJWTKey)
+}
+
+func (a *jwtAuthenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	token, err := a.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request mints a new one.
+func (a *jwtAuthenticator) Invalidate() {
+	e := a.cache.entry(a.name, a.key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.token = nil
+}
+
+func (a *jwtAuthenticator) token(ctx context.Context) (*oauth2.Token, error) {
+	e := a.cache.entry(a.name, a.key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.token != nil && a.now().Add(a.config.RefreshBefore).Before(e.token.Expiry) {
+		return e.token, nil
+	}
+
+	token, err := a.mint()
+	if err != nil {
+		return nil, errors.Wrap(err, errMintJWT)
+	}
+
+	if a.config.TokenURL != "" {
+		exchanged, err := a.exchange(ctx, token.AccessToken)
+		if err != nil {
+			return nil, errors.Wrap(err, errExchangeJWT)
+		}
+
+		// Access tokens without an expiry are renewed along with the JWT.
+		if exchanged.Expiry.IsZero() {
+			exchanged.Expiry = token.Expiry
+		}
+		token = exchanged
+	}
+
+	e.token = token
+	return token, nil
+}
+
+// mint returns a new JWT signed with the private key.
+func (a *jwtAuthenticator) mint() (*oauth2.Token, error) {
+	now := a.now()
+	expiry := now.Add(a.config.Lifetime)
+
+	claims := jwt.MapClaims{
+		"iat": jwt.NewNumericDate(now.Add(-jwtClockSkew)),
+		"exp": jwt.NewNumericDate(expiry),
+	}
+	if a.config.Issuer != "" {
+		claims["iss"] = a.config.Issuer
+	}
+	if a.config.Subject != "" {
+		claims["sub"] = a.config.Subject
+	}
+	if a.config.Audience != "" {
+		claims["aud"] = a.config.Audience
+	}
+	if len(a.config.Scopes) > 0 {
+		claims["scope"] = strings.Join(a.config.Scopes, " ")
+	}
+
+	t := jwt.NewWithClaims(a.method, claims)
+	if a.config.KeyID != "" {
+		t.Header["kid"] = a.config.KeyID
+	}
+
+	signed, err := t.SignedString(a.signingKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{AccessToken: signed, TokenType: "Bearer", Expiry: expiry}, nil
+}
+
+// exchangeResponse covers the OAuth2 token response as well as the GitHub
+// App installation token response.
+type exchangeResponse struct {
+	AccessToken string     `json:"access_token"`
+	Token       string     `json:"token"`
+	TokenType   string     `json:"token_type"`
+	ExpiresIn   int64      `json:"expires_in"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+}
+
+// exchangeClient returns the HTTP client of ctx, set under the
+// oauth2.HTTPClient key, so the token URL is reached through the TLS and proxy
+// settings of the ProviderConfig.
+func exchangeClient(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && client != nil {
+		return client
+	}
+	return &http.Client{Timeout: defaultExchangeTimeout}
+}
+
+// exchangeStatusError returns the error of a failed token exchange. The
+// response body may echo credentials, so only its OAuth2 error code is kept.
+func exchangeStatusError(statusCode int, body []byte) error {
+	var response struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &response) == nil && response.Error != "" {
+		return errors.Errorf(errExchangeRejected, statusCode, response.Error)
+	}
+	return errors.Errorf(errExchangeStatus, statusCode)
+}
+
+// exchange trades the signed JWT for an access token at the token URL.
+func (a *jwtAuthenticator) exchange(ctx context.Context, assertion string) (*oauth2.Token, error) {
+	var request *http.Request
+	var err error
+	if a.config.ExchangeStyle == JWTExchangeBearer {
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, a.config.TokenURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Authorization", "Bearer "+assertion)
+	} else {
+		form := url.Values{"grant_type": {grantTypeJWTBearer}, "assertion": {assertion}}
+		request, err = http.NewRequestWithContext(ctx, http.MethodPost, a.config.TokenURL, strings.NewReader(form.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := exchangeClient(ctx).Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close() //nolint:errcheck
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return nil, exchangeStatusError(response.StatusCode, body)
+	}
+
+	var exchanged exchangeResponse
+	if err := json.Unmarshal(body, &exchanged); err != nil {
+		return nil, errors.Wrap(err, errExchangeResponse)
+	}
+
+	token := &oauth2.Token{AccessToken: exchanged.AccessToken, TokenType: exchanged.TokenType}
+	if token.AccessToken == "" {
+		token.AccessToken = exchanged.Token
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New(errExchangeNoToken)
+	}
+	if token.TokenType == "" {
+		token.TokenType = "Bearer"
+	}
+
+	switch {
+	case exchanged.ExpiresAt != nil:
+		token.Expiry = *exchanged.ExpiresAt
+	case exchanged.ExpiresIn > 0:
+		token.Expiry = a.now().Add(time.Duration(exchanged.ExpiresIn) * time.Second)
+	}
+
+	return token, nil
+}
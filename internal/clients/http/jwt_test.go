@@ -0,0 +1,338 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func newTestECKey(t *testing.T) (*ecdsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("cannot generate key: %s", err)
+	}
+
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("cannot marshal key: %s", err)
+	}
+
+	return key, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})
+}
+
+// parseTestJWT verifies the JWT signature and returns its kid and claims.
+func parseTestJWT(key *ecdsa.PrivateKey, token string) (string, jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	parsed, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	}, jwt.WithValidMethods([]string{"ES256"}))
+	if err != nil {
+		return "", nil, err
+	}
+
+	kid, _ := parsed.Header["kid"].(string)
+	return kid, claims, nil
+}
+
+func Test_jwtAuthenticator(t *testing.T) {
+	key, keyPEM := newTestECKey(t)
+	now := time.Now().Truncate(time.Second)
+
+	config := JWTConfig{
+		Algorithm:  "ES256",
+		PrivateKey: keyPEM,
+		KeyID:      "key-1",
+		Issuer:     "123456",
+		Subject:    "svc@example.iam",
+		Audience:   "https://oauth2.example.com/token",
+		Scopes:     []string{"read", "write"},
+		Lifetime:   10 * time.Minute,
+	}
+
+	// The token endpoints verify the JWT they receive before issuing a token.
+	grant := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.Form.Get("grant_type") != grantTypeJWTBearer {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if _, _, err := parseTestJWT(key, r.Form.Get("assertion")); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprintf(w, `{"error":"invalid_grant","error_description":"cannot verify %s"}`, r.Form.Get("assertion"))
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"google-token","token_type":"Bearer","expires_in":3600}`)
+	}))
+	defer grant.Close()
+
+	bearer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, _, err := parseTestJWT(key, strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"A JSON web token could not be decoded"}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token":"ghs_token","expires_at":%q}`, now.Add(time.Hour).Format(time.RFC3339))
+	}))
+	defer bearer.Close()
+
+	withExchange := func(url, style string) JWTConfig {
+		c := config
+		c.TokenURL = url
+		c.ExchangeStyle = style
+		return c
+	}
+
+	type args struct {
+		config JWTConfig
+	}
+	type want struct {
+		authorization string
+		claims        jwt.MapClaims
+		err           error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Assertion": {
+			args: args{
+				config: config,
+			},
+			want: want{
+				claims: jwt.MapClaims{
+					"iss":   "123456",
+					"sub":   "svc@example.iam",
+					"aud":   "https://oauth2.example.com/token",
+					"scope": "read write",
+					"iat":   float64(now.Add(-jwtClockSkew).Unix()),
+					"exp":   float64(now.Add(10 * time.Minute).Unix()),
+				},
+			},
+		},
+		"JWTBearerGrant": {
+			args: args{
+				config: withExchange(grant.URL, JWTExchangeGrant),
+			},
+			want: want{
+				authorization: "Bearer google-token",
+			},
+		},
+		"BearerExchange": {
+			args: args{
+				config: withExchange(bearer.URL, JWTExchangeBearer),
+			},
+			want: want{
+				authorization: "Bearer ghs_token",
+			},
+		},
+		"ExchangeRejected": {
+			args: args{
+				config: func() JWTConfig {
+					_, otherPEM := newTestECKey(t)
+					c := withExchange(bearer.URL, JWTExchangeBearer)
+					c.PrivateKey = otherPEM
+					return c
+				}(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errExchangeStatus, http.StatusUnauthorized), errExchangeJWT),
+			},
+		},
+		"GrantRejected": {
+			args: args{
+				config: func() JWTConfig {
+					_, otherPEM := newTestECKey(t)
+					c := withExchange(grant.URL, JWTExchangeGrant)
+					c.PrivateKey = otherPEM
+					return c
+				}(),
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf(errExchangeRejected, http.StatusUnauthorized, "invalid_grant"), errExchangeJWT),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := NewJWTAuthenticator(name, tc.args.config)
+			if err != nil {
+				t.Fatalf("NewJWTAuthenticator(...): unexpected error: %s", err)
+			}
+			a.(*jwtAuthenticator).cache = newTokenCache()
+			a.(*jwtAuthenticator).now = func() time.Time { return now }
+
+			request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			err = a.Authenticate(context.Background(), request)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Authenticate(...): -want error, +got error: %s", diff)
+			}
+
+			if tc.want.claims == nil {
+				if diff := cmp.Diff(tc.want.authorization, request.Header.Get("Authorization")); diff != "" {
+					t.Fatalf("Authenticate(...): -want authorization header, +got authorization header: %s", diff)
+				}
+				return
+			}
+
+			kid, claims, err := parseTestJWT(key, strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer "))
+			if err != nil {
+				t.Fatalf("Authenticate(...): invalid JWT: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.args.config.KeyID, kid); diff != "" {
+				t.Fatalf("Authenticate(...): -want kid, +got kid: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.claims, claims); diff != "" {
+				t.Fatalf("Authenticate(...): -want claims, +got claims: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_jwtAuthenticatorRotation(t *testing.T) {
+	_, keyPEM := newTestECKey(t)
+	now := time.Now()
+
+	a, err := NewJWTAuthenticator("rotation", JWTConfig{
+		Algorithm:  "ES256",
+		PrivateKey: keyPEM,
+		Issuer:     "123456",
+		Lifetime:   10 * time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator(...): unexpected error: %s", err)
+	}
+	authenticator := a.(*jwtAuthenticator)
+	authenticator.cache = newTokenCache()
+	authenticator.now = func() time.Time { return now }
+
+	authorization := func() string {
+		request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+		if err := a.Authenticate(context.Background(), request); err != nil {
+			t.Fatalf("Authenticate(...): unexpected error: %s", err)
+		}
+		return request.Header.Get("Authorization")
+	}
+
+	first := authorization()
+
+	now = now.Add(5 * time.Minute)
+	if got := authorization(); got != first {
+		t.Fatalf("Authenticate(...): minted a new JWT while the cached one is still valid")
+	}
+
+	// Within RefreshBefore of the expiry the JWT is renewed.
+	now = now.Add(4*time.Minute + time.Second)
+	second := authorization()
+	if second == first {
+		t.Fatalf("Authenticate(...): kept a JWT about to expire")
+	}
+
+	a.Invalidate()
+	if got := authorization(); got == second {
+		t.Fatalf("Authenticate(...): kept an invalidated JWT")
+	}
+}
+
+func Test_SendRequestExchangesJWTThroughProxy(t *testing.T) {
+	_, keyPEM := newTestECKey(t)
+
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token-1","token_type":"Bearer","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer proxy.Close()
+
+	a, err := NewJWTAuthenticator("proxied", JWTConfig{
+		Algorithm:  "ES256",
+		PrivateKey: keyPEM,
+		TokenURL:   "http://idp.internal/token",
+	})
+	if err != nil {
+		t.Fatalf("NewJWTAuthenticator(...): unexpected error: %s", err)
+	}
+	a.(*jwtAuthenticator).cache = newTokenCache()
+
+	// The token URL is only reachable through the proxy.
+	c, _ := NewClient(logging.NewNopLogger(), time.Minute,
+		WithProxyConfig(ProxyConfig{URL: proxy.URL}),
+		WithAuthenticator(a))
+
+	if _, err := c.SendRequest(context.Background(), http.MethodGet, "http://api.internal/v1/users", "", nil, false); err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	want := []string{"POST http://idp.internal/token", "GET http://api.internal/v1/users"}
+	if diff := cmp.Diff(want, proxied); diff != "" {
+		t.Fatalf("SendRequest(...): -want proxied requests, +got proxied requests: %s", diff)
+	}
+}
+
+func Test_NewJWTAuthenticator(t *testing.T) {
+	_, keyPEM := newTestECKey(t)
+
+	type args struct {
+		config JWTConfig
+	}
+	cases := map[string]struct {
+		args args
+		want error
+	}{
+		"UnknownAlgorithm": {
+			args: args{
+				config: JWTConfig{Algorithm: "HS256", PrivateKey: keyPEM},
+			},
+			want: errors.Errorf(errUnknownJWTAlgo, "HS256"),
+		},
+		"NoneAlgorithm": {
+			args: args{
+				config: JWTConfig{Algorithm: "none", PrivateKey: keyPEM},
+			},
+			want: errors.Errorf(errUnknownJWTAlgo, "none"),
+		},
+		"InvalidKey": {
+			args: args{
+				config: JWTConfig{Algorithm: "RS256", PrivateKey: []byte("not-a-key")},
+			},
+			want: errors.Wrap(jwt.ErrKeyMustBePEMEncoded, errParseJWTKey),
+		},
+		"UnknownExchange": {
+			args: args{
+				config: JWTConfig{Algorithm: "ES256", PrivateKey: keyPEM, ExchangeStyle: "Basic"},
+			},
+			want: errors.Errorf(errUnknownExchange, "Basic"),
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewJWTAuthenticator(name, tc.args.config)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("NewJWTAuthenticator(...): -want error, +got error: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+const (
+	errFetchToken = "cannot fetch oauth2 token"
+
+	defaultRefreshBefore = time.Minute
+)
+
+// OAuth2Config describes an OAuth2 client credentials flow.
+type OAuth2Config struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	Audience     string
+
+	// RefreshBefore is how long before its expiry a cached token is renewed.
+	RefreshBefore time.Duration
+}
+
+// key identifies the token issued for this configuration.
+func (c OAuth2Config) key() string {
+	hash := sha256.Sum256([]byte(strings.Join([]string{
+		c.TokenURL, c.ClientID, c.ClientSecret, c.Audience, strings.Join(c.Scopes, " "),
+	}, "\n")))
+	return hex.EncodeToString(hash[:])
+}
+
+// tokenCache holds the access tokens shared by all clients of the provider,
+// one per ProviderConfig.
+type tokenCache struct {
+	mu      sync.Mutex
+	entries map[string]*cachedToken
+}
+
+type cachedToken struct {
+	mu    sync.Mutex
+	token *oauth2.Token
+
+	// config identifies the configuration the token was issued for.
+	config string
+}
+
+var defaultTokenCache = newTokenCache()
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{entries: map[string]*cachedToken{}}
+}
+
+// entry returns the token of the given ProviderConfig, replaced by an empty
+// one once its configuration changed.
+func (c *tokenCache) entry(providerConfig, config string) *cachedToken {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[providerConfig]
+	if !ok || e.config != config {
+		e = &cachedToken{config: config}
+		c.entries[providerConfig] = e
+	}
+
+	return e
+}
+
+// evict forgets the tokens issued for the given ProviderConfig.
+func (c *tokenCache) evict(providerConfig string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, providerConfig)
+}
+
+type oauth2Authenticator struct {
+	name   string
+	key    string
+	config OAuth2Config
+	cache  *tokenCache
+}
+
+// NewOAuth2Authenticator returns an Authenticator that obtains access tokens
+// through the OAuth2 client credentials flow. Tokens are cached per name,
+// typically the ProviderConfig name, and renewed shortly before they expire.
+func NewOAuth2Authenticator(name string, config OAuth2Config) RefreshableAuthenticator {
+	if config.RefreshBefore == 0 {
+		config.RefreshBefore = defaultRefreshBefore
+	}
+
+	return &oauth2Authenticator{
+		name:   name,
+		key:    config.key(),
+		config: config,
+		cache:  defaultTokenCache,
+	}
+}
+
+func (a *oauth2Authenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	token, err := a.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", token.Type()+" "+token.AccessToken)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request fetches a new one.
+func (a *oauth2Authenticator) Invalidate() {
+	e := a.cache.entry(a.name, a.key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.token = nil
+}
+
+func (a *oauth2Authenticator) token(ctx context.Context) (*oauth2.Token, error) {
+	e := a.cache.entry(a.name, a.key)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if a.isValid(e.token) {
+		return e.token, nil
+	}
+
+	token, err := a.fetch(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errFetchToken)
+	}
+
+	e.token = token
+	return token, nil
+}
+
+// isValid reports whether the token can still be used, renewing it
+// RefreshBefore ahead of its expiry.
+func (a *oauth2Authenticator) isValid(token *oauth2.Token) bool {
+	if token == nil || token.AccessToken == "" {
+		return false
+	}
+
+	if token.Expiry.IsZero() {
+		return true
+	}
+
+	return time.Now().Add(a.config.RefreshBefore).Before(token.Expiry)
+}
+
+// fetch requests a new token with the HTTP client of ctx, set under the
+// oauth2.HTTPClient key, so the token endpoint is reached through the
+// transport of the ProviderConfig.
+func (a *oauth2Authenticator) fetch(ctx context.Context) (*oauth2.Token, error) {
+	cc := &clientcredentials.Config{
+		ClientID:     a.config.ClientID,
+		ClientSecret: a.config.ClientSecret,
+		TokenURL:     a.config.TokenURL,
+		Scopes:       a.config.Scopes,
+	}
+
+	if a.config.Audience != "" {
+		cc.EndpointParams = url.Values{"audience": {a.config.Audience}}
+	}
+
+	token, err := cc.Token(ctx)
+	var rejected *oauth2.RetrieveError
+	if errors.As(err, &rejected) {
+		return nil, exchangeStatusError(rejected.Response.StatusCode, rejected.Body)
+	}
+	return token, err
+}
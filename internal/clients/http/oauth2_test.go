@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+// newTokenServer returns a token endpoint issuing "token-<n>" access tokens
+// that expire after expiresIn seconds.
+func newTokenServer(t *testing.T, expiresIn int, issued *int32) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("ParseForm(): %s", err)
+		}
+
+		if got := r.PostForm.Get("grant_type"); got != "client_credentials" {
+			t.Errorf("grant_type: want client_credentials, got %s", got)
+		}
+
+		if got := r.PostForm.Get("audience"); got != "https://api.example.com" {
+			t.Errorf("audience: want https://api.example.com, got %s", got)
+		}
+
+		n := atomic.AddInt32(issued, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"bearer","expires_in":%d}`, n, expiresIn)
+	}))
+}
+
+func Test_OAuth2Authenticator(t *testing.T) {
+	type args struct {
+		expiresIn int
+		requests  int
+	}
+	type want struct {
+		issued int32
+		header string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"CachedUntilExpiry": {
+			args: args{
+				expiresIn: 3600,
+				requests:  3,
+			},
+			want: want{
+				issued: 1,
+				header: "Bearer token-1",
+			},
+		},
+		"RefreshedEarly": {
+			args: args{
+				expiresIn: 30,
+				requests:  3,
+			},
+			want: want{
+				issued: 3,
+				header: "Bearer token-3",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var issued int32
+			server := newTokenServer(t, tc.args.expiresIn, &issued)
+			defer server.Close()
+
+			a := NewOAuth2Authenticator(name, OAuth2Config{
+				TokenURL:      server.URL,
+				ClientID:      "client",
+				ClientSecret:  "secret",
+				Audience:      "https://api.example.com",
+				RefreshBefore: time.Minute,
+			})
+
+			var header string
+			for i := 0; i < tc.args.requests; i++ {
+				request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+				if err := a.Authenticate(context.Background(), request); err != nil {
+					t.Fatalf("Authenticate(...): unexpected error: %s", err)
+				}
+				header = request.Header.Get("Authorization")
+			}
+
+			if diff := cmp.Diff(tc.want.issued, atomic.LoadInt32(&issued)); diff != "" {
+				t.Fatalf("Authenticate(...): -want issued tokens, +got issued tokens: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.header, header); diff != "" {
+				t.Fatalf("Authenticate(...): -want authorization header, +got authorization header: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestRetriesUnauthorized(t *testing.T) {
+	var issued int32
+	tokenServer := newTokenServer(t, 3600, &issued)
+	defer tokenServer.Close()
+
+	// The API only accepts the second token, as if the first one was revoked.
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer api.Close()
+
+	c, _ := NewClient(logging.NewNopLogger(), time.Minute, WithAuthenticator(NewOAuth2Authenticator("retry", OAuth2Config{
+		TokenURL:     tokenServer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Audience:     "https://api.example.com",
+	})))
+
+	details, err := c.SendRequest(context.Background(), http.MethodGet, api.URL, "", nil, false)
+	if err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(http.StatusOK, details.HttpResponse.StatusCode); diff != "" {
+		t.Fatalf("SendRequest(...): -want status code, +got status code: %s", diff)
+	}
+
+	if diff := cmp.Diff(int32(2), atomic.LoadInt32(&issued)); diff != "" {
+		t.Fatalf("SendRequest(...): -want issued tokens, +got issued tokens: %s", diff)
+	}
+}
+
+func Test_SendRequestFetchesTokenThroughProxy(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.Method+" "+r.URL.String())
+		if r.URL.Path == "/token" {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"token-1","token_type":"bearer","expires_in":3600}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer proxy.Close()
+
+	// The identity provider is only reachable through the proxy.
+	c, _ := NewClient(logging.NewNopLogger(), time.Minute,
+		WithProxyConfig(ProxyConfig{URL: proxy.URL}),
+		WithAuthenticator(NewOAuth2Authenticator("proxied", OAuth2Config{
+			TokenURL:     "http://idp.internal/token",
+			ClientID:     "client",
+			ClientSecret: "secret",
+		})))
+
+	if _, err := c.SendRequest(context.Background(), http.MethodGet, "http://api.internal/v1/users", "", nil, false); err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	want := []string{"POST http://idp.internal/token", "GET http://api.internal/v1/users"}
+	if diff := cmp.Diff(want, proxied); diff != "" {
+		t.Fatalf("SendRequest(...): -want proxied requests, +got proxied requests: %s", diff)
+	}
+}
+
+func Test_tokenCacheRotation(t *testing.T) {
+	var issued int32
+	server := newTokenServer(t, 3600, &issued)
+	defer server.Close()
+
+	c := newTokenCache()
+	authenticate := func(secret string) {
+		a := NewOAuth2Authenticator("http-conf", OAuth2Config{
+			TokenURL:     server.URL,
+			ClientID:     "client",
+			ClientSecret: secret,
+			Audience:     "https://api.example.com",
+		}).(*oauth2Authenticator)
+		a.cache = c
+
+		request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+		if err := a.Authenticate(context.Background(), request); err != nil {
+			t.Fatalf("Authenticate(...): unexpected error: %s", err)
+		}
+	}
+
+	// Rotating the client secret replaces the token of the ProviderConfig.
+	for _, secret := range []string{"secret-1", "secret-2", "secret-2"} {
+		authenticate(secret)
+	}
+	if diff := cmp.Diff(1, len(c.entries)); diff != "" {
+		t.Fatalf("Authenticate(...): -want cached tokens, +got cached tokens: %s", diff)
+	}
+	if diff := cmp.Diff(int32(2), atomic.LoadInt32(&issued)); diff != "" {
+		t.Fatalf("Authenticate(...): -want issued tokens, +got issued tokens: %s", diff)
+	}
+
+	c.evict("http-conf")
+	if diff := cmp.Diff(0, len(c.entries)); diff != "" {
+		t.Fatalf("evict(...): -want cached tokens, +got cached tokens: %s", diff)
+	}
+}
+
+func Test_OAuth2AuthenticatorRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client","error_description":"unknown client secret s3cr3t"}`)
+	}))
+	defer server.Close()
+
+	a := NewOAuth2Authenticator("http-conf", OAuth2Config{
+		TokenURL:     server.URL,
+		ClientID:     "client",
+		ClientSecret: "s3cr3t",
+	}).(*oauth2Authenticator)
+	a.cache = newTokenCache()
+
+	request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	err := a.Authenticate(context.Background(), request)
+	want := errors.Wrap(errors.Errorf(errExchangeRejected, http.StatusUnauthorized, "invalid_client"), errFetchToken)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("Authenticate(...): -want error, +got error: %s", diff)
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/http/httpproxy"
+)
+
+const (
+	errParseProxyURL = "cannot parse proxy url"
+)
+
+// ProxyConfig describes the outbound proxy of requests.
+type ProxyConfig struct {
+	URL      string
+	Username string
+	Password string
+	NoProxy  []string
+
+	// FromEnvironment uses the HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+	// environment variables when URL is not set.
+	FromEnvironment bool
+}
+
+// WithProxyConfig sets the proxy used by every request.
+func WithProxyConfig(config ProxyConfig) Option {
+	return func(c *client) {
+		c.proxyConfig = config
+	}
+}
+
+// proxyFunc is the proxy selection function of a transport.
+type proxyFunc func(*url.URL) (*url.URL, error)
+
+// newProxyFunc returns the function selecting the proxy of a request URL, or
+// nil when no proxy is configured.
+func newProxyFunc(config ProxyConfig) (proxyFunc, error) {
+	if config.URL == "" {
+		if config.FromEnvironment {
+			return httpproxy.FromEnvironment().ProxyFunc(), nil
+		}
+		return nil, nil
+	}
+
+	proxyURL, err := url.Parse(config.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseProxyURL)
+	}
+
+	if config.Username != "" {
+		proxyURL.User = url.UserPassword(config.Username, config.Password)
+	}
+
+	return (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    strings.Join(config.NoProxy, ","),
+	}).ProxyFunc(), nil
+}
+
+// transportProxy adapts a proxyFunc to the Proxy field of http.Transport.
+func transportProxy(proxy proxyFunc) func(*http.Request) (*url.URL, error) {
+	if proxy == nil {
+		return nil
+	}
+
+	return func(request *http.Request) (*url.URL, error) {
+		return proxy(request.URL)
+	}
+}
+
+// proxyFor returns the proxy a request to rawURL is sent through, without its
+// credentials, or an empty string if the request is sent directly.
+func (hc *client) proxyFor(rawURL string) string {
+	proxy, err := newProxyFunc(hc.proxyConfig)
+	if proxy == nil || err != nil {
+		return ""
+	}
+
+	requestURL, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+
+	proxyURL, err := proxy(requestURL)
+	if proxyURL == nil || err != nil {
+		return ""
+	}
+
+	proxyURL.User = nil
+	return proxyURL.String()
+}
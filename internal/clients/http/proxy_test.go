@@ -0,0 +1,109 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_proxyFor(t *testing.T) {
+	type args struct {
+		config ProxyConfig
+		url    string
+	}
+	cases := map[string]struct {
+		args args
+		want string
+	}{
+		"NoProxy": {
+			args: args{
+				url: "https://api.example.com/v1/users",
+			},
+			want: "",
+		},
+		"Proxy": {
+			args: args{
+				config: ProxyConfig{URL: "http://proxy.internal:3128"},
+				url:    "https://api.example.com/v1/users",
+			},
+			want: "http://proxy.internal:3128",
+		},
+		"CredentialsNotReported": {
+			args: args{
+				config: ProxyConfig{URL: "http://proxy.internal:3128", Username: "user", Password: "secret"},
+				url:    "https://api.example.com/v1/users",
+			},
+			want: "http://proxy.internal:3128",
+		},
+		"NoProxyHost": {
+			args: args{
+				config: ProxyConfig{URL: "http://proxy.internal:3128", NoProxy: []string{"api.example.com"}},
+				url:    "https://api.example.com/v1/users",
+			},
+			want: "",
+		},
+		"NoProxyDomain": {
+			args: args{
+				config: ProxyConfig{URL: "http://proxy.internal:3128", NoProxy: []string{".example.com"}},
+				url:    "https://api.example.com/v1/users",
+			},
+			want: "",
+		},
+		"NoProxyCIDR": {
+			args: args{
+				config: ProxyConfig{URL: "http://proxy.internal:3128", NoProxy: []string{"10.0.0.0/8"}},
+				url:    "http://10.1.2.3/v1/users",
+			},
+			want: "",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &client{proxyConfig: tc.args.config}
+			got := c.proxyFor(tc.args.url)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("proxyFor(...): -want proxy, +got proxy: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestThroughProxy(t *testing.T) {
+	var proxied string
+	var proxyAuthorization string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		proxyAuthorization = r.Header.Get("Proxy-Authorization")
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer proxy.Close()
+
+	c, _ := NewClient(logging.NewNopLogger(), time.Minute, WithProxyConfig(ProxyConfig{
+		URL:      proxy.URL,
+		Username: "user",
+		Password: "secret",
+	}))
+
+	details, err := c.SendRequest(context.Background(), http.MethodGet, "http://api.example.com/v1/users", "", nil, false)
+	if err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff("http://api.example.com/v1/users", proxied); diff != "" {
+		t.Fatalf("SendRequest(...): -want proxied url, +got proxied url: %s", diff)
+	}
+
+	if diff := cmp.Diff("Basic dXNlcjpzZWNyZXQ=", proxyAuthorization); diff != "" {
+		t.Fatalf("SendRequest(...): -want proxy authorization, +got proxy authorization: %s", diff)
+	}
+
+	if diff := cmp.Diff(proxy.URL, details.HttpRequest.Proxy); diff != "" {
+		t.Fatalf("SendRequest(...): -want reported proxy, +got reported proxy: %s", diff)
+	}
+}
@@ -0,0 +1,237 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	errRateLimited = "cannot send request to %s within its rate limits"
+
+	anyHost = "*"
+
+	// minRetryAfter is the least delay before a rate limited request is sent
+	// again, e.g. when a concurrency slot of its host is awaited.
+	minRetryAfter = time.Second
+)
+
+// HostRateLimit limits the requests sent to a target host. Zero values are
+// unlimited.
+type HostRateLimit struct {
+	Host              string
+	RequestsPerSecond int
+	Burst             int
+	MaxInFlight       int
+}
+
+// WithRateLimits sets the limits of the requests sent to each target host.
+func WithRateLimits(limits []HostRateLimit) Option {
+	return func(c *client) {
+		c.rateLimits = limits
+	}
+}
+
+// RateLimitedError is returned when a request could not be sent within the
+// limits of its host before its context expired.
+type RateLimitedError struct {
+	Host string
+	Err  error
+
+	// RetryAfter is how long to wait before sending the request again.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return fmt.Sprintf(errRateLimited, e.Host) + ": " + e.Err.Error()
+}
+
+func (e *RateLimitedError) Unwrap() error {
+	return e.Err
+}
+
+// IsRateLimited reports whether err was caused by the rate limits of a host.
+// Such requests were not sent and should be requeued, not counted as failed.
+func IsRateLimited(err error) bool {
+	var rateLimited *RateLimitedError
+	return errors.As(err, &rateLimited)
+}
+
+// RetryAfter returns how long to wait before sending again the request err
+// was returned for, if it was rate limited.
+func RetryAfter(err error) (time.Duration, bool) {
+	var rateLimited *RateLimitedError
+	if !errors.As(err, &rateLimited) {
+		return 0, false
+	}
+	return rateLimited.RetryAfter, true
+}
+
+// rateLimitFor returns the limits applying to the given host:port.
+func rateLimitFor(limits []HostRateLimit, host string) (HostRateLimit, bool) {
+	hostname, _, err := net.SplitHostPort(host)
+	if err != nil {
+		hostname = host
+	}
+
+	var fallback *HostRateLimit
+	for i, limit := range limits {
+		if limit.Host == host || limit.Host == hostname {
+			return limit, true
+		}
+		if limit.Host == anyHost && fallback == nil {
+			fallback = &limits[i]
+		}
+	}
+
+	if fallback != nil {
+		return *fallback, true
+	}
+	return HostRateLimit{}, false
+}
+
+// hostLimiter enforces the limits of a host.
+type hostLimiter struct {
+	limit    HostRateLimit
+	tokens   *rate.Limiter
+	inFlight chan struct{}
+}
+
+func newHostLimiter(limit HostRateLimit) *hostLimiter {
+	l := &hostLimiter{limit: limit}
+
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = limit.RequestsPerSecond
+		}
+		l.tokens = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+
+	if limit.MaxInFlight > 0 {
+		l.inFlight = make(chan struct{}, limit.MaxInFlight)
+	}
+
+	return l
+}
+
+// acquire waits until a request can be sent to the host, returning the
+// function releasing its concurrency slot.
+func (l *hostLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.inFlight != nil {
+		select {
+		case l.inFlight <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.inFlight != nil {
+			<-l.inFlight
+		}
+	}
+
+	if l.tokens != nil {
+		if err := l.tokens.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	return release, nil
+}
+
+// retryAfter returns how long until a request can be sent to the host.
+func (l *hostLimiter) retryAfter() time.Duration {
+	if l.tokens == nil {
+		return minRetryAfter
+	}
+
+	r := l.tokens.Reserve()
+	defer r.Cancel()
+
+	if d := r.Delay(); d > minRetryAfter {
+		return d
+	}
+	return minRetryAfter
+}
+
+// limiterRegistry holds the limiters shared by all clients of the provider,
+// so the limits of a ProviderConfig hold across resources and controllers.
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*hostLimiter
+}
+
+var defaultLimiterRegistry = newLimiterRegistry()
+
+func newLimiterRegistry() *limiterRegistry {
+	return &limiterRegistry{limiters: map[string]*hostLimiter{}}
+}
+
+// get returns the limiter of the host for the given ProviderConfig, replacing
+// it if its limits changed.
+func (r *limiterRegistry) get(providerConfig, host string, limit HostRateLimit) *hostLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := providerConfig + "/" + host
+	if l, ok := r.limiters[key]; ok && l.limit == limit {
+		return l
+	}
+
+	l := newHostLimiter(limit)
+	r.limiters[key] = l
+	return l
+}
+
+// evict forgets the limiters of the given ProviderConfig.
+func (r *limiterRegistry) evict(providerConfig string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key := range r.limiters {
+		if strings.HasPrefix(key, providerConfig+"/") {
+			delete(r.limiters, key)
+		}
+	}
+}
+
+// waitForLimits waits until the request can be sent within the limits of its
+// host. The returned function must be called once the request is done.
+func (hc *client) waitForLimits(ctx context.Context, request *http.Request) (func(), error) {
+	limit, ok := rateLimitFor(hc.rateLimits, request.URL.Host)
+	if !ok {
+		return func() {}, nil
+	}
+
+	l := hc.limiters.get(hc.providerConfig, request.URL.Host, limit)
+	release, err := l.acquire(ctx)
+	if err != nil {
+		return nil, &RateLimitedError{Host: request.URL.Host, Err: err, RetryAfter: l.retryAfter()}
+	}
+
+	return release, nil
+}
+
+// releaseOnClose calls release once the body is closed.
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
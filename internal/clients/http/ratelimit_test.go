@@ -0,0 +1,183 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_rateLimitFor(t *testing.T) {
+	limits := []HostRateLimit{
+		{Host: "*", RequestsPerSecond: 50},
+		{Host: "api.example.com", RequestsPerSecond: 5},
+		{Host: "legacy.example.com:8443", MaxInFlight: 1},
+	}
+
+	type want struct {
+		limit HostRateLimit
+		ok    bool
+	}
+	cases := map[string]struct {
+		limits []HostRateLimit
+		host   string
+		want   want
+	}{
+		"NoLimits": {
+			host: "api.example.com",
+			want: want{},
+		},
+		"Hostname": {
+			limits: limits,
+			host:   "api.example.com",
+			want:   want{limit: limits[1], ok: true},
+		},
+		"HostnameWithPort": {
+			limits: limits,
+			host:   "api.example.com:443",
+			want:   want{limit: limits[1], ok: true},
+		},
+		"HostAndPort": {
+			limits: limits,
+			host:   "legacy.example.com:8443",
+			want:   want{limit: limits[2], ok: true},
+		},
+		"Fallback": {
+			limits: limits,
+			host:   "legacy.example.com",
+			want:   want{limit: limits[0], ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			limit, ok := rateLimitFor(tc.limits, tc.host)
+			if diff := cmp.Diff(tc.want, want{limit: limit, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Fatalf("rateLimitFor(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestRateLimits(t *testing.T) {
+	cases := map[string]struct {
+		limit HostRateLimit
+		hold  bool
+		want  bool
+	}{
+		"WithinLimits": {
+			limit: HostRateLimit{Host: "*", RequestsPerSecond: 100, MaxInFlight: 2},
+			want:  false,
+		},
+		"RequestsPerSecondExceeded": {
+			limit: HostRateLimit{Host: "*", RequestsPerSecond: 1},
+			want:  true,
+		},
+		"MaxInFlightExceeded": {
+			limit: HostRateLimit{Host: "*", MaxInFlight: 1},
+			hold:  true,
+			want:  true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"id":"123"}`)
+			}))
+			defer server.Close()
+
+			c := &client{
+				log:             logging.NewNopLogger(),
+				timeout:         time.Minute,
+				retryPolicy:     RetryPolicy{MaxAttempts: 1},
+				transportConfig: DefaultTransportConfig,
+				transports:      newTransportCache(),
+				limiters:        newLimiterRegistry(),
+				rateLimits:      []HostRateLimit{tc.limit},
+				providerConfig:  name,
+			}
+
+			if tc.hold {
+				// Keep a request in flight until the end of the test.
+				request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+				release, err := c.waitForLimits(context.Background(), request)
+				if err != nil {
+					t.Fatalf("waitForLimits(...): unexpected error: %s", err)
+				}
+				defer release()
+			} else if _, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false); err != nil {
+				t.Fatalf("SendRequest(...): unexpected error: %s", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+			defer cancel()
+
+			_, err := c.SendRequest(ctx, http.MethodGet, server.URL, "", nil, false)
+			if diff := cmp.Diff(tc.want, IsRateLimited(err)); diff != "" {
+				t.Fatalf("SendRequest(...): -want rate limited, +got rate limited: %s", diff)
+			}
+
+			if after, ok := RetryAfter(err); ok && after < minRetryAfter {
+				t.Fatalf("RetryAfter(...): want at least %s, got %s", minRetryAfter, after)
+			}
+		})
+	}
+}
+
+func Test_SendRequestReleasesLimitsOnReadError(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			// Announce a longer body than sent, so reading it fails.
+			w.Header().Set("Content-Length", "100")
+			fmt.Fprint(w, `{"id":`)
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+	defer server.Close()
+
+	c := &client{
+		log:             logging.NewNopLogger(),
+		timeout:         time.Minute,
+		retryPolicy:     RetryPolicy{MaxAttempts: 1},
+		transportConfig: DefaultTransportConfig,
+		transports:      newTransportCache(),
+		limiters:        newLimiterRegistry(),
+		rateLimits:      []HostRateLimit{{Host: "*", MaxInFlight: 1}},
+		providerConfig:  "read-error",
+	}
+
+	if _, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false); err == nil {
+		t.Fatalf("SendRequest(...): expected an error reading the body")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.SendRequest(ctx, http.MethodGet, server.URL, "", nil, false); err != nil {
+		t.Fatalf("SendRequest(...): unexpected error, the failed request kept its in-flight slot: %s", err)
+	}
+}
+
+func Test_limiterRegistryEvict(t *testing.T) {
+	r := newLimiterRegistry()
+	limit := HostRateLimit{Host: anyHost, RequestsPerSecond: 1}
+	evicted := r.get("http-conf", "api.example.com", limit)
+	kept := r.get("http-conf-other", "api.example.com", limit)
+
+	r.evict("http-conf")
+
+	if r.get("http-conf", "api.example.com", limit) == evicted {
+		t.Fatalf("evict(...): limiter of the evicted ProviderConfig still kept")
+	}
+	if r.get("http-conf-other", "api.example.com", limit) != kept {
+		t.Fatalf("evict(...): limiter of another ProviderConfig not kept")
+	}
+}
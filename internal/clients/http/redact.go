@@ -0,0 +1,190 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseRedactedPath = "cannot parse redacted body path %s"
+
+	// RedactedValue replaces the values hidden from the logs.
+	RedactedValue = "[REDACTED]"
+
+	// MaskedValue replaces the values hidden from the status of resources.
+	MaskedValue = "***"
+)
+
+// DefaultRedactedHeaders are the headers whose values are never logged.
+var DefaultRedactedHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+	"X-Api-Key",
+	"Proxy-Authorization",
+}
+
+// RedactionConfig describes what is hidden from the logs in addition to the
+// DefaultRedactedHeaders.
+type RedactionConfig struct {
+	// Headers whose values are redacted.
+	Headers []string
+
+	// BodyPaths are jq paths of the JSON body values redacted, e.g.
+	// .password or .users[].token.
+	BodyPaths []string
+}
+
+// WithRedaction sets what is hidden from the logs of the client.
+func WithRedaction(config RedactionConfig) Option {
+	return func(c *client) {
+		c.redaction = config
+	}
+}
+
+// Redactor hides sensitive header and body values of requests and responses
+// before they are logged. A nil Redactor hides the DefaultRedactedHeaders.
+type Redactor struct {
+	headers map[string]bool
+	body    *gojq.Code
+	value   string
+}
+
+var defaultRedactor, _ = NewRedactor(RedactionConfig{})
+
+// NewRedactor returns a Redactor hiding the DefaultRedactedHeaders and the
+// headers and body paths of config.
+func NewRedactor(config RedactionConfig) (*Redactor, error) {
+	return newRedactor(append(append([]string{}, DefaultRedactedHeaders...), config.Headers...), config.BodyPaths, RedactedValue)
+}
+
+// NewMasker returns a Redactor replacing only the headers and body paths of
+// config with MaskedValue, as written to the status of resources.
+func NewMasker(config RedactionConfig) (*Redactor, error) {
+	return newRedactor(config.Headers, config.BodyPaths, MaskedValue)
+}
+
+func newRedactor(headers, bodyPaths []string, value string) (*Redactor, error) {
+	r := &Redactor{headers: map[string]bool{}, value: value}
+	for _, h := range headers {
+		r.headers[http.CanonicalHeaderKey(h)] = true
+	}
+
+	if len(bodyPaths) == 0 {
+		return r, nil
+	}
+
+	paths := make([]string, len(bodyPaths))
+	for i, p := range bodyPaths {
+		if _, err := gojq.Parse(p); err != nil {
+			return nil, errors.Wrapf(err, errParseRedactedPath, p)
+		}
+		// Paths that don't apply to the body, or are missing from it, are
+		// skipped rather than created.
+		paths[i] = "(try path(" + p + ") catch empty)"
+	}
+
+	query, err := gojq.Parse("reduce (" + strings.Join(paths, ", ") + ") as $p (.; " +
+		"if getpath($p) == null then . else setpath($p; $redacted) end)")
+	if err != nil {
+		return nil, errors.Wrapf(err, errParseRedactedPath, strings.Join(bodyPaths, ", "))
+	}
+
+	if r.body, err = gojq.Compile(query, gojq.WithVariables([]string{"$redacted"})); err != nil {
+		return nil, errors.Wrapf(err, errParseRedactedPath, strings.Join(bodyPaths, ", "))
+	}
+
+	return r, nil
+}
+
+// Headers returns a copy of headers with the sensitive values redacted.
+func (r *Redactor) Headers(headers map[string][]string) map[string][]string {
+	if r == nil {
+		r = defaultRedactor
+	}
+
+	if headers == nil {
+		return nil
+	}
+
+	redacted := make(map[string][]string, len(headers))
+	for name, values := range headers {
+		if !r.headers[http.CanonicalHeaderKey(name)] {
+			redacted[name] = values
+			continue
+		}
+
+		redacted[name] = make([]string, len(values))
+		for i := range values {
+			redacted[name][i] = r.value
+		}
+	}
+
+	return redacted
+}
+
+// Body returns body with the values at the redacted paths hidden. A body
+// that is not JSON is hidden as a whole once body paths are configured.
+func (r *Redactor) Body(body string) string {
+	if r == nil || r.body == nil || body == "" {
+		return body
+	}
+
+	var obj interface{}
+	if err := json.Unmarshal([]byte(body), &obj); err != nil {
+		return r.value
+	}
+
+	result, ok := r.body.Run(obj, r.value).Next()
+	if !ok {
+		return r.value
+	}
+	if _, isErr := result.(error); isErr {
+		return r.value
+	}
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return r.value
+	}
+
+	return string(b)
+}
+
+// URL returns rawURL with the password of its user info redacted.
+func (r *Redactor) URL(rawURL string) string {
+	if r == nil {
+		r = defaultRedactor
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User == nil {
+		return rawURL
+	}
+
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), r.value)
+	}
+
+	return u.String()
+}
+
+// Request returns a copy of request with its sensitive values redacted.
+func (r *Redactor) Request(request HttpRequest) HttpRequest {
+	request.URL = r.URL(request.URL)
+	request.Headers = r.Headers(request.Headers)
+	request.Body = r.Body(request.Body)
+	return request
+}
+
+// Response returns a copy of response with its sensitive values redacted.
+func (r *Redactor) Response(response HttpResponse) HttpResponse {
+	response.Headers = r.Headers(response.Headers)
+	response.Body = r.Body(response.Body)
+	return response
+}
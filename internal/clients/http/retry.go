@@ -0,0 +1,230 @@
+package http
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NetworkError is a class of network errors a request can be retried on.
+type NetworkError string
+
+// Network errors a request can be retried on.
+const (
+	NetworkErrorTimeout           NetworkError = "Timeout"
+	NetworkErrorConnectionRefused NetworkError = "ConnectionRefused"
+	NetworkErrorConnectionReset   NetworkError = "ConnectionReset"
+	NetworkErrorDNS               NetworkError = "DNS"
+)
+
+// RetryPolicy describes how failed requests are retried with exponential
+// backoff.
+type RetryPolicy struct {
+	MaxAttempts            int
+	BaseBackoff            time.Duration
+	MaxBackoff             time.Duration
+	Jitter                 bool
+	RetryableMethods       []string
+	RetryableStatusCodes   []int
+	RetryableNetworkErrors []NetworkError
+	HonorRetryAfter        bool
+}
+
+// DefaultRetryPolicy is the retry policy used when a ProviderConfig does not
+// configure one.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseBackoff: 200 * time.Millisecond,
+	MaxBackoff:  5 * time.Second,
+	Jitter:      true,
+	// POST and PATCH are not idempotent, retrying them once the first attempt
+	// reached the server may apply them twice.
+	RetryableMethods: []string{
+		http.MethodGet,
+		http.MethodHead,
+		http.MethodPut,
+		http.MethodDelete,
+		http.MethodOptions,
+	},
+	RetryableStatusCodes: []int{
+		http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+	RetryableNetworkErrors: []NetworkError{
+		NetworkErrorTimeout,
+		NetworkErrorConnectionRefused,
+		NetworkErrorConnectionReset,
+		NetworkErrorDNS,
+	},
+	HonorRetryAfter: true,
+}
+
+// WithRetryPolicy sets the retry policy of every request.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *client) {
+		c.retryPolicy = policy
+	}
+}
+
+// RetryOverride derives the retry policy of a request from the one of the
+// client.
+type RetryOverride func(RetryPolicy) RetryPolicy
+
+type retryOverrideKey struct{}
+
+// ContextWithRetryOverride returns a context whose requests use the retry
+// policy derived by override, e.g. to apply the policy of a single mapping.
+func ContextWithRetryOverride(ctx context.Context, override RetryOverride) context.Context {
+	if override == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, retryOverrideKey{}, override)
+}
+
+// retryPolicyFor returns the retry policy of the requests sent with ctx.
+func (hc *client) retryPolicyFor(ctx context.Context) RetryPolicy {
+	if override, ok := ctx.Value(retryOverrideKey{}).(RetryOverride); ok {
+		return override(hc.retryPolicy)
+	}
+	return hc.retryPolicy
+}
+
+// shouldRetry reports whether the outcome of an attempt of a request with the
+// given method is retryable under the policy.
+func (p RetryPolicy) shouldRetry(method string, response *http.Response, err error) bool {
+	if !p.retriesMethod(method) {
+		return false
+	}
+
+	if err != nil {
+		class, ok := classifyNetworkError(err)
+		if !ok {
+			return false
+		}
+
+		for _, retryable := range p.RetryableNetworkErrors {
+			if retryable == class {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, code := range p.RetryableStatusCodes {
+		if response.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p RetryPolicy) retriesMethod(method string) bool {
+	for _, retryable := range p.RetryableMethods {
+		if strings.EqualFold(retryable, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns how long to wait before the given retry, starting at 1, and
+// whether the request should be retried at all.
+func (p RetryPolicy) backoff(retry int, response *http.Response) (time.Duration, bool) {
+	if p.HonorRetryAfter && response != nil &&
+		(response.StatusCode == http.StatusTooManyRequests || response.StatusCode == http.StatusServiceUnavailable) {
+		if delay, ok := retryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+			// Waiting longer than the policy allows would hold the reconcile,
+			// leave it to the next poll instead.
+			return delay, delay <= p.MaxBackoff
+		}
+	}
+
+	delay := p.BaseBackoff
+	for i := 1; i < retry && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	if p.Jitter && delay > 0 {
+		// #nosec G404 -- jitter does not need a cryptographic source.
+		delay = time.Duration(rand.Int63n(int64(delay) + 1))
+	}
+
+	return delay, true
+}
+
+// retryAfter parses the value of a Retry-After header, given either in
+// seconds or as an HTTP date.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
+
+// classifyNetworkError returns the class of a network error, if it is one.
+func classifyNetworkError(err error) (NetworkError, bool) {
+	if errors.Is(err, context.Canceled) || IsRateLimited(err) {
+		return "", false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return NetworkErrorDNS, true
+	}
+
+	switch {
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return NetworkErrorConnectionRefused, true
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return NetworkErrorConnectionReset, true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return NetworkErrorTimeout, true
+	}
+
+	return "", false
+}
+
+// sleep waits for delay, returning early with an error if ctx is done.
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,317 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_retryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	type want struct {
+		delay time.Duration
+		ok    bool
+	}
+	cases := map[string]struct {
+		value string
+		want  want
+	}{
+		"Empty": {
+			value: "",
+			want:  want{},
+		},
+		"Seconds": {
+			value: "3",
+			want:  want{delay: 3 * time.Second, ok: true},
+		},
+		"NegativeSeconds": {
+			value: "-3",
+			want:  want{},
+		},
+		"Date": {
+			value: "Mon, 01 Jan 2024 12:00:10 GMT",
+			want:  want{delay: 10 * time.Second, ok: true},
+		},
+		"PastDate": {
+			value: "Mon, 01 Jan 2024 11:00:00 GMT",
+			want:  want{delay: 0, ok: true},
+		},
+		"Invalid": {
+			value: "soon",
+			want:  want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			delay, ok := retryAfter(tc.value, now)
+			if diff := cmp.Diff(tc.want, want{delay: delay, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Fatalf("retryAfter(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_backoff(t *testing.T) {
+	policy := RetryPolicy{
+		BaseBackoff:     100 * time.Millisecond,
+		MaxBackoff:      time.Second,
+		HonorRetryAfter: true,
+	}
+
+	type args struct {
+		retry    int
+		response *http.Response
+	}
+	type want struct {
+		delay time.Duration
+		ok    bool
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"FirstRetry": {
+			args: args{retry: 1},
+			want: want{delay: 100 * time.Millisecond, ok: true},
+		},
+		"Exponential": {
+			args: args{retry: 3},
+			want: want{delay: 400 * time.Millisecond, ok: true},
+		},
+		"Capped": {
+			args: args{retry: 10},
+			want: want{delay: time.Second, ok: true},
+		},
+		"RetryAfter": {
+			args: args{retry: 1, response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": {"1"}},
+			}},
+			want: want{delay: time.Second, ok: true},
+		},
+		"RetryAfterBeyondMaxBackoff": {
+			args: args{retry: 1, response: &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": {"120"}},
+			}},
+			want: want{delay: 120 * time.Second, ok: false},
+		},
+		"RetryAfterIgnoredOnOtherCodes": {
+			args: args{retry: 1, response: &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Header:     http.Header{"Retry-After": {"120"}},
+			}},
+			want: want{delay: 100 * time.Millisecond, ok: true},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			delay, ok := policy.backoff(tc.args.retry, tc.args.response)
+			if diff := cmp.Diff(tc.want, want{delay: delay, ok: ok}, cmp.AllowUnexported(want{})); diff != "" {
+				t.Fatalf("backoff(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestRetries(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:          3,
+		BaseBackoff:          time.Millisecond,
+		MaxBackoff:           10 * time.Millisecond,
+		RetryableMethods:     []string{http.MethodGet},
+		RetryableStatusCodes: []int{http.StatusBadGateway, http.StatusServiceUnavailable},
+		HonorRetryAfter:      true,
+	}
+
+	type args struct {
+		responses []int
+		header    http.Header
+		override  RetryOverride
+	}
+	type want struct {
+		statusCode int
+		attempts   int32
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"TransientFailure": {
+			args: args{
+				responses: []int{http.StatusBadGateway, http.StatusOK},
+			},
+			want: want{
+				statusCode: http.StatusOK,
+				attempts:   2,
+			},
+		},
+		"NotRetryable": {
+			args: args{
+				responses: []int{http.StatusInternalServerError, http.StatusOK},
+			},
+			want: want{
+				statusCode: http.StatusInternalServerError,
+				attempts:   1,
+			},
+		},
+		"AttemptsExhausted": {
+			args: args{
+				responses: []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			},
+			want: want{
+				statusCode: http.StatusBadGateway,
+				attempts:   3,
+			},
+		},
+		"RetryAfterTooLong": {
+			args: args{
+				responses: []int{http.StatusServiceUnavailable, http.StatusOK},
+				header:    http.Header{"Retry-After": {"60"}},
+			},
+			want: want{
+				statusCode: http.StatusServiceUnavailable,
+				attempts:   1,
+			},
+		},
+		"OverriddenPolicy": {
+			args: args{
+				responses: []int{http.StatusBadGateway, http.StatusOK},
+				override: func(p RetryPolicy) RetryPolicy {
+					p.MaxAttempts = 1
+					return p
+				},
+			},
+			want: want{
+				statusCode: http.StatusBadGateway,
+				attempts:   1,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				attempt := atomic.AddInt32(&attempts, 1)
+				for key, values := range tc.args.header {
+					w.Header()[key] = values
+				}
+				w.WriteHeader(tc.args.responses[attempt-1])
+				fmt.Fprint(w, `{"id":"123"}`)
+			}))
+			defer server.Close()
+
+			c, _ := NewClient(logging.NewNopLogger(), time.Minute, WithRetryPolicy(policy))
+
+			ctx := ContextWithRetryOverride(context.Background(), tc.args.override)
+			details, err := c.SendRequest(ctx, http.MethodGet, server.URL, "", nil, false)
+			if err != nil {
+				t.Fatalf("SendRequest(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want.statusCode, details.HttpResponse.StatusCode); diff != "" {
+				t.Fatalf("SendRequest(...): -want status code, +got status code: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.attempts, atomic.LoadInt32(&attempts)); diff != "" {
+				t.Fatalf("SendRequest(...): -want attempts, +got attempts: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_SendRequestRetriesMethods(t *testing.T) {
+	type args struct {
+		method   string
+		override RetryOverride
+	}
+	type want struct {
+		err      bool
+		attempts int32
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"PostNotRetried": {
+			args: args{
+				method: http.MethodPost,
+			},
+			want: want{
+				err:      true,
+				attempts: 1,
+			},
+		},
+		"PatchNotRetried": {
+			args: args{
+				method: http.MethodPatch,
+			},
+			want: want{
+				err:      true,
+				attempts: 1,
+			},
+		},
+		"PutRetried": {
+			args: args{
+				method: http.MethodPut,
+			},
+			want: want{
+				attempts: 2,
+			},
+		},
+		"PostRetriedOnOptIn": {
+			args: args{
+				method: http.MethodPost,
+				override: func(p RetryPolicy) RetryPolicy {
+					p.RetryableMethods = append(p.RetryableMethods, http.MethodPost)
+					return p
+				},
+			},
+			want: want{
+				attempts: 2,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			// The first attempt reaches the server, which resets the
+			// connection before answering.
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) == 1 {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Errorf("Hijack(): %s", err)
+						return
+					}
+					_ = conn.Close()
+					return
+				}
+				fmt.Fprint(w, `{"id":"123"}`)
+			}))
+			defer server.Close()
+
+			policy := DefaultRetryPolicy
+			policy.BaseBackoff = time.Millisecond
+			c, _ := NewClient(logging.NewNopLogger(), time.Minute, WithRetryPolicy(policy))
+
+			ctx := ContextWithRetryOverride(context.Background(), tc.args.override)
+			_, err := c.SendRequest(ctx, tc.args.method, server.URL, `{"name":"john"}`, nil, false)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("SendRequest(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.attempts, atomic.LoadInt32(&attempts)); diff != "" {
+				t.Fatalf("SendRequest(...): -want attempts, +got attempts: %s", diff)
+			}
+		})
+	}
+}
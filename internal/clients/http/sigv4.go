@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	errReadBody = "cannot read request body"
+	errSignV4   = "cannot sign request with SigV4"
+)
+
+// SigV4Config describes how requests are signed with AWS Signature Version 4.
+type SigV4Config struct {
+	Region          string
+	Service         string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+type sigV4Authenticator struct {
+	config SigV4Config
+	signer *v4.Signer
+	now    func() time.Time
+}
+
+// NewSigV4Authenticator returns an Authenticator that signs every request,
+// including its body, with AWS Signature Version 4.
+func NewSigV4Authenticator(config SigV4Config) Authenticator {
+	return &sigV4Authenticator{
+		config: config,
+		signer: v4.NewSigner(),
+		now:    time.Now,
+	}
+}
+
+func (a *sigV4Authenticator) Authenticate(ctx context.Context, request *http.Request) error {
+	payloadHash, err := hashBody(request)
+	if err != nil {
+		return errors.Wrap(err, errReadBody)
+	}
+
+	credentials := aws.Credentials{
+		AccessKeyID:     a.config.AccessKeyID,
+		SecretAccessKey: a.config.SecretAccessKey,
+		SessionToken:    a.config.SessionToken,
+	}
+
+	err = a.signer.SignHTTP(ctx, credentials, request, payloadHash, a.config.Service, a.config.Region, a.now())
+	return errors.Wrap(err, errSignV4)
+}
+
+// hashBody returns the hex encoded SHA-256 of the request body, leaving the
+// body readable.
+func hashBody(request *http.Request) (string, error) {
+	body, err := readBody(request)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,174 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+const (
+	testAccessKeyID     = "AKIDEXAMPLE"
+	testSecretAccessKey = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+)
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// expectedSigV4 computes the signature of the received request independently
+// of the signer, following the AWS Signature Version 4 specification.
+func expectedSigV4(r *http.Request, body []byte, region, service string) (string, error) {
+	auth := r.Header.Get("Authorization")
+	parts := map[string]string{}
+	for _, part := range strings.Split(strings.TrimPrefix(auth, "AWS4-HMAC-SHA256 "), ", ") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("malformed authorization header: %s", auth)
+		}
+		parts[kv[0]] = kv[1]
+	}
+
+	amzDate := r.Header.Get("X-Amz-Date")
+	signedHeaders := strings.Split(parts["SignedHeaders"], ";")
+
+	canonicalHeaders := ""
+	for _, h := range signedHeaders {
+		value := r.Header.Get(h)
+		switch h {
+		case "host":
+			value = r.Host
+		case "content-length":
+			value = fmt.Sprint(r.ContentLength)
+		}
+		canonicalHeaders += h + ":" + strings.TrimSpace(value) + "\n"
+	}
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	canonicalQuery := make([]string, 0, len(keys))
+	for _, k := range keys {
+		canonicalQuery = append(canonicalQuery, k+"="+query.Get(k))
+	}
+
+	bodyHash := sha256.Sum256(body)
+	canonicalRequest := strings.Join([]string{
+		r.Method,
+		r.URL.EscapedPath(),
+		strings.Join(canonicalQuery, "&"),
+		canonicalHeaders,
+		parts["SignedHeaders"],
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+
+	scope := strings.Join([]string{amzDate[:8], region, service, "aws4_request"}, "/")
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+testSecretAccessKey), amzDate[:8])
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+
+	if parts["Credential"] != testAccessKeyID+"/"+scope {
+		return "", fmt.Errorf("unexpected credential scope: %s", parts["Credential"])
+	}
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign)), nil
+}
+
+func newSigV4Server(t *testing.T, region, service string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		expected, err := expectedSigV4(r, body, region, service)
+		if err != nil || !strings.HasSuffix(r.Header.Get("Authorization"), "Signature="+expected) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		fmt.Fprint(w, `{"id":"123"}`)
+	}))
+}
+
+func Test_SendRequestSigV4(t *testing.T) {
+	type args struct {
+		method          string
+		path            string
+		body            string
+		secretAccessKey string
+	}
+	cases := map[string]struct {
+		args args
+		want int
+	}{
+		"SignedGet": {
+			args: args{
+				method:          http.MethodGet,
+				path:            "/prod/users?page=2&limit=10",
+				secretAccessKey: testSecretAccessKey,
+			},
+			want: http.StatusOK,
+		},
+		"SignedBody": {
+			args: args{
+				method:          http.MethodPost,
+				path:            "/prod/users",
+				body:            `{"username":"john_doe"}`,
+				secretAccessKey: testSecretAccessKey,
+			},
+			want: http.StatusOK,
+		},
+		"WrongKey": {
+			args: args{
+				method:          http.MethodPost,
+				path:            "/prod/users",
+				body:            `{"username":"john_doe"}`,
+				secretAccessKey: "wrong",
+			},
+			want: http.StatusForbidden,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := newSigV4Server(t, "eu-west-1", "execute-api")
+			defer server.Close()
+
+			c, _ := NewClient(logging.NewNopLogger(), time.Minute, WithAuthenticator(NewSigV4Authenticator(SigV4Config{
+				Region:          "eu-west-1",
+				Service:         "execute-api",
+				AccessKeyID:     testAccessKeyID,
+				SecretAccessKey: tc.args.secretAccessKey,
+			})))
+
+			headers := map[string][]string{"Content-Type": {"application/json"}}
+			details, err := c.SendRequest(context.Background(), tc.args.method, server.URL+tc.args.path, tc.args.body, headers, false)
+			if err != nil {
+				t.Fatalf("SendRequest(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want, details.HttpResponse.StatusCode); diff != "" {
+				t.Fatalf("SendRequest(...): -want status code, +got status code: %s", diff)
+			}
+		})
+	}
+}
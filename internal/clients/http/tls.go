@@ -0,0 +1,71 @@
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errAppendCABundle     = "cannot append CA bundle: no valid PEM certificates found"
+	errIncompleteKeyPair  = "client certificate and key must be set together"
+	errLoadClientKeyPair  = "cannot load client certificate key pair"
+	errLoadSystemCertPool = "cannot load system certificate pool"
+)
+
+// TLSConfigData holds the PEM encoded certificates and settings used to build
+// the TLS configuration of requests.
+type TLSConfigData struct {
+	CABundle   []byte
+	ClientCert []byte
+	ClientKey  []byte
+	MinVersion uint16
+	ServerName string
+}
+
+// WithTLSConfigData sets the TLS configuration used by every request.
+func WithTLSConfigData(data TLSConfigData) Option {
+	return func(c *client) {
+		c.tlsConfigData = data
+	}
+}
+
+// newTLSConfig builds the tls.Config described by data. Certificates of the CA
+// bundle are trusted in addition to the system ones.
+func newTLSConfig(data TLSConfigData, skipTLSVerify bool) (*tls.Config, error) {
+	config := &tls.Config{
+		// #nosec G402
+		InsecureSkipVerify: skipTLSVerify,
+		MinVersion:         data.MinVersion,
+		ServerName:         data.ServerName,
+	}
+
+	if len(data.CABundle) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadSystemCertPool)
+		}
+
+		if !pool.AppendCertsFromPEM(data.CABundle) {
+			return nil, errors.New(errAppendCABundle)
+		}
+
+		config.RootCAs = pool
+	}
+
+	if (len(data.ClientCert) > 0) != (len(data.ClientKey) > 0) {
+		return nil, errors.New(errIncompleteKeyPair)
+	}
+
+	if len(data.ClientCert) > 0 {
+		certificate, err := tls.X509KeyPair(data.ClientCert, data.ClientKey)
+		if err != nil {
+			return nil, errors.Wrap(err, errLoadClientKeyPair)
+		}
+
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
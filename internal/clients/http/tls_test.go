@@ -0,0 +1,185 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/google/go-cmp/cmp"
+)
+
+type testCertificate struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+// newTestCertificate issues a certificate signed by parent, or a self-signed
+// CA certificate when parent is nil.
+func newTestCertificate(t *testing.T, parent *testCertificate, template *x509.Certificate) *testCertificate {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("ecdsa.GenerateKey(...): %s", err)
+	}
+
+	template.SerialNumber = big.NewInt(time.Now().UnixNano())
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("x509.CreateCertificate(...): %s", err)
+	}
+
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	return &testCertificate{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// newMutualTLSServer starts a server trusting only client certificates
+// signed by ca.
+func newMutualTLSServer(t *testing.T, ca *testCertificate) *httptest.Server {
+	t.Helper()
+
+	serverCert := newTestCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "api.internal"},
+		DNSNames:    []string{"api.internal"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	})
+
+	keyPair, err := tls.X509KeyPair(serverCert.certPEM, serverCert.keyPEM)
+	if err != nil {
+		t.Fatalf("tls.X509KeyPair(...): %s", err)
+	}
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca.cert)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{keyPair},
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+	server.StartTLS()
+
+	return server
+}
+
+func Test_SendRequestTLS(t *testing.T) {
+	ca := newTestCertificate(t, nil, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	})
+	clientCert := newTestCertificate(t, ca, &x509.Certificate{
+		Subject:     pkix.Name{CommonName: "provider-http"},
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+
+	server := newMutualTLSServer(t, ca)
+	defer server.Close()
+
+	type args struct {
+		data TLSConfigData
+	}
+	type want struct {
+		statusCode int
+		err        bool
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"MutualTLS": {
+			args: args{
+				data: TLSConfigData{
+					CABundle:   ca.certPEM,
+					ClientCert: clientCert.certPEM,
+					ClientKey:  clientCert.keyPEM,
+					MinVersion: tls.VersionTLS12,
+					ServerName: "api.internal",
+				},
+			},
+			want: want{
+				statusCode: http.StatusOK,
+			},
+		},
+		"UnknownCA": {
+			args: args{
+				data: TLSConfigData{
+					ClientCert: clientCert.certPEM,
+					ClientKey:  clientCert.keyPEM,
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"MissingClientCertificate": {
+			args: args{
+				data: TLSConfigData{
+					CABundle: ca.certPEM,
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
+		"IncompleteKeyPair": {
+			args: args{
+				data: TLSConfigData{
+					CABundle:   ca.certPEM,
+					ClientCert: clientCert.certPEM,
+				},
+			},
+			want: want{
+				err: true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, _ := NewClient(logging.NewNopLogger(), 10*time.Second, WithTLSConfigData(tc.args.data))
+			details, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false)
+			if diff := cmp.Diff(tc.want.err, err != nil); diff != "" {
+				t.Fatalf("SendRequest(...): -want error, +got error: %s: %v", diff, err)
+			}
+
+			if diff := cmp.Diff(tc.want.statusCode, details.HttpResponse.StatusCode); diff != "" {
+				t.Fatalf("SendRequest(...): -want status code, +got status code: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errStatTokenFile  = "cannot stat token file"
+	errReadTokenFile  = "cannot read token file"
+	errEmptyTokenFile = "token file %s is empty"
+)
+
+type tokenFileAuthenticator struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+}
+
+// NewTokenFileAuthenticator returns an Authenticator that sends the token
+// stored in the file at path as bearer token, e.g. a projected service
+// account token. The file is read again whenever it changes, so rotated
+// tokens are picked up without reconnecting.
+func NewTokenFileAuthenticator(path string) RefreshableAuthenticator {
+	return &tokenFileAuthenticator{path: path}
+}
+
+func (a *tokenFileAuthenticator) Authenticate(_ context.Context, request *http.Request) error {
+	token, err := a.read()
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// Invalidate drops the cached token so the next request reads the file again.
+func (a *tokenFileAuthenticator) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.token = ""
+}
+
+// read returns the token of the file, reading it again if it was modified
+// since it was last read.
+func (a *tokenFileAuthenticator) read() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(a.path)
+	if err != nil {
+		return "", errors.Wrap(err, errStatTokenFile)
+	}
+
+	if a.token != "" && info.ModTime().Equal(a.modTime) {
+		return a.token, nil
+	}
+
+	b, err := os.ReadFile(a.path)
+	if err != nil {
+		return "", errors.Wrap(err, errReadTokenFile)
+	}
+
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", errors.Errorf(errEmptyTokenFile, a.path)
+	}
+
+	a.token, a.modTime = token, info.ModTime()
+	return token, nil
+}
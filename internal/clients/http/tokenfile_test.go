@@ -0,0 +1,74 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func Test_tokenFileAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	modTime := time.Now()
+
+	// writeToken writes the token file with a distinct modification time, as
+	// the kubelet does when it rotates a projected token.
+	writeToken := func(token string) {
+		if err := os.WriteFile(path, []byte(token), 0o600); err != nil {
+			t.Fatalf("cannot write token file: %s", err)
+		}
+		modTime = modTime.Add(time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("cannot touch token file: %s", err)
+		}
+	}
+
+	a := NewTokenFileAuthenticator(path)
+
+	type want struct {
+		header string
+		err    error
+	}
+	steps := []struct {
+		name  string
+		token string
+		want  want
+	}{
+		{
+			name:  "Initial",
+			token: "first-token\n",
+			want:  want{header: "Bearer first-token"},
+		},
+		{
+			name:  "Rotated",
+			token: "second-token",
+			want:  want{header: "Bearer second-token"},
+		},
+		{
+			name:  "Empty",
+			token: " \n",
+			want:  want{err: errors.Errorf(errEmptyTokenFile, path)},
+		},
+	}
+	for _, step := range steps {
+		t.Run(step.name, func(t *testing.T) {
+			writeToken(step.token)
+
+			request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			err := a.Authenticate(context.Background(), request)
+			if diff := cmp.Diff(step.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Authenticate(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(step.want.header, request.Header.Get("Authorization")); diff != "" {
+				t.Fatalf("Authenticate(...): -want authorization header, +got authorization header: %s", diff)
+			}
+		})
+	}
+}
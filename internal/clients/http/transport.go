@@ -0,0 +1,226 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errBuildTLSConfig = "cannot build TLS config"
+	errHashTransport  = "cannot hash transport settings"
+)
+
+// TransportConfig configures the connection pool of a transport.
+type TransportConfig struct {
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+}
+
+// DefaultTransportConfig is the connection pool used when a ProviderConfig
+// does not configure one.
+var DefaultTransportConfig = TransportConfig{
+	MaxIdleConns:        100,
+	MaxIdleConnsPerHost: 10,
+	IdleConnTimeout:     90 * time.Second,
+}
+
+// WithTransportConfig sets the connection pool settings of the transport.
+func WithTransportConfig(config TransportConfig) Option {
+	return func(c *client) {
+		c.transportConfig = config
+	}
+}
+
+// WithProviderConfig scopes the transport of the client to the given
+// ProviderConfig generation. Clients of a ProviderConfig share a transport per
+// settings, replaced once a newer generation is used.
+func WithProviderConfig(name string, generation int64) Option {
+	return func(c *client) {
+		c.providerConfig = name
+		c.generation = generation
+	}
+}
+
+// transportSettings are the settings a transport is built from.
+type transportSettings struct {
+	TLSConfigData   TLSConfigData
+	SkipTLSVerify   bool
+	TransportConfig TransportConfig
+	ProxyConfig     ProxyConfig
+}
+
+func (s transportSettings) hash() (string, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// maxTransportsPerProviderConfig bounds the transports kept for the
+// different settings of a ProviderConfig, e.g. the TLS settings of its
+// resources or the rotations of a referenced TLS Secret.
+const maxTransportsPerProviderConfig = 16
+
+// transportKey identifies the transport of a ProviderConfig built from the
+// settings of the given hash.
+type transportKey struct {
+	providerConfig string
+	settings       string
+}
+
+type cachedTransport struct {
+	transport *http.Transport
+	used      uint64
+}
+
+// transportCache holds the transports shared by all clients of the provider,
+// so connections are kept alive and reused across reconciles. Each
+// ProviderConfig has a transport per settings its resources use, built from
+// its latest generation.
+type transportCache struct {
+	mu          sync.Mutex
+	transports  map[transportKey]*cachedTransport
+	generations map[string]int64
+	uses        uint64
+}
+
+var defaultTransportCache = newTransportCache()
+
+func newTransportCache() *transportCache {
+	return &transportCache{transports: map[transportKey]*cachedTransport{}, generations: map[string]int64{}}
+}
+
+// get returns the cached transport of the given ProviderConfig and settings,
+// building it if needed. A newer generation replaces all the cached
+// transports of the ProviderConfig and closes their idle connections. Past
+// maxTransportsPerProviderConfig settings, the least recently used transport
+// of the ProviderConfig is closed.
+func (c *transportCache) get(providerConfig string, generation int64, settings transportSettings) (*http.Transport, error) {
+	hash, err := settings.hash()
+	if err != nil {
+		return nil, errors.Wrap(err, errHashTransport)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	cachedGeneration, ok := c.generations[providerConfig]
+	if ok && generation < cachedGeneration {
+		// A stale view of the ProviderConfig, don't let it repopulate the
+		// cache with outdated settings.
+		transport, err := newTransport(settings)
+		if err != nil {
+			return nil, err
+		}
+		transport.DisableKeepAlives = true
+		return transport, nil
+	}
+
+	if ok && generation > cachedGeneration {
+		c.evictLocked(providerConfig)
+	}
+	c.generations[providerConfig] = generation
+
+	c.uses++
+	key := transportKey{providerConfig: providerConfig, settings: hash}
+	if cached, ok := c.transports[key]; ok {
+		cached.used = c.uses
+		return cached.transport, nil
+	}
+
+	transport, err := newTransport(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	c.transports[key] = &cachedTransport{transport: transport, used: c.uses}
+	c.evictLeastRecentlyUsedLocked(providerConfig)
+	return transport, nil
+}
+
+// evictLeastRecentlyUsedLocked closes the least recently used transport of
+// the given ProviderConfig once it has too many.
+func (c *transportCache) evictLeastRecentlyUsedLocked(providerConfig string) {
+	var oldest *transportKey
+	count := 0
+	for key, cached := range c.transports {
+		if key.providerConfig != providerConfig {
+			continue
+		}
+		count++
+		if oldest == nil || cached.used < c.transports[*oldest].used {
+			k := key
+			oldest = &k
+		}
+	}
+
+	if count > maxTransportsPerProviderConfig {
+		c.transports[*oldest].transport.CloseIdleConnections()
+		delete(c.transports, *oldest)
+	}
+}
+
+// evictLocked closes and removes the transports of the given ProviderConfig.
+func (c *transportCache) evictLocked(providerConfig string) {
+	for key, cached := range c.transports {
+		if key.providerConfig == providerConfig {
+			cached.transport.CloseIdleConnections()
+			delete(c.transports, key)
+		}
+	}
+}
+
+// evict closes and removes the transports of the given ProviderConfig.
+func (c *transportCache) evict(providerConfig string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evictLocked(providerConfig)
+	delete(c.generations, providerConfig)
+}
+
+// EvictProviderConfig closes the transports and forgets the circuit breakers,
+// rate limiters and tokens of the given ProviderConfig, typically once it is
+// deleted.
+func EvictProviderConfig(providerConfig string) {
+	defaultTransportCache.evict(providerConfig)
+	defaultBreakerRegistry.evict(providerConfig)
+	defaultLimiterRegistry.evict(providerConfig)
+	defaultTokenCache.evict(providerConfig)
+}
+
+func newTransport(settings transportSettings) (*http.Transport, error) {
+	tlsConfig, err := newTLSConfig(settings.TLSConfigData, settings.SkipTLSVerify)
+	if err != nil {
+		return nil, errors.Wrap(err, errBuildTLSConfig)
+	}
+
+	proxy, err := newProxyFunc(settings.ProxyConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Transport{
+		Proxy: transportProxy(proxy),
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: 10 * time.Second,
+		MaxIdleConns:        settings.TransportConfig.MaxIdleConns,
+		MaxIdleConnsPerHost: settings.TransportConfig.MaxIdleConnsPerHost,
+		IdleConnTimeout:     settings.TransportConfig.IdleConnTimeout,
+	}, nil
+}
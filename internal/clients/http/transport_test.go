@@ -0,0 +1,187 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_transportCache(t *testing.T) {
+	type call struct {
+		providerConfig string
+		generation     int64
+		settings       transportSettings
+	}
+	type want struct {
+		sameTransport bool
+		keepAlives    bool
+		cachedEntries int
+	}
+	base := call{providerConfig: "http-conf", generation: 1, settings: transportSettings{TransportConfig: DefaultTransportConfig}}
+
+	cases := map[string]struct {
+		first  call
+		second call
+		want   want
+	}{
+		"SameSettingsReused": {
+			first:  base,
+			second: base,
+			want: want{
+				sameTransport: true,
+				keepAlives:    true,
+				cachedEntries: 1,
+			},
+		},
+		"DifferentSettingsCached": {
+			first: base,
+			second: call{providerConfig: "http-conf", generation: 1, settings: transportSettings{
+				TLSConfigData:   TLSConfigData{ServerName: "api.example.com"},
+				TransportConfig: DefaultTransportConfig,
+			}},
+			want: want{
+				keepAlives:    true,
+				cachedEntries: 2,
+			},
+		},
+		"DifferentProviderConfig": {
+			first:  base,
+			second: call{providerConfig: "other-conf", generation: 1, settings: base.settings},
+			want: want{
+				keepAlives:    true,
+				cachedEntries: 2,
+			},
+		},
+		"NewerGenerationReplaces": {
+			first:  base,
+			second: call{providerConfig: "http-conf", generation: 2, settings: base.settings},
+			want: want{
+				keepAlives:    true,
+				cachedEntries: 1,
+			},
+		},
+		"StaleGenerationNotCached": {
+			first:  call{providerConfig: "http-conf", generation: 2, settings: base.settings},
+			second: base,
+			want: want{
+				keepAlives:    false,
+				cachedEntries: 1,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cache := newTransportCache()
+
+			first, err := cache.get(tc.first.providerConfig, tc.first.generation, tc.first.settings)
+			if err != nil {
+				t.Fatalf("get(...): unexpected error: %s", err)
+			}
+
+			second, err := cache.get(tc.second.providerConfig, tc.second.generation, tc.second.settings)
+			if err != nil {
+				t.Fatalf("get(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want.sameTransport, first == second); diff != "" {
+				t.Fatalf("get(...): -want same transport, +got same transport: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.keepAlives, !second.DisableKeepAlives); diff != "" {
+				t.Fatalf("get(...): -want keep-alives, +got keep-alives: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.cachedEntries, len(cache.transports)); diff != "" {
+				t.Fatalf("get(...): -want cached transports, +got cached transports: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_transportCacheEvict(t *testing.T) {
+	cache := newTransportCache()
+	settings := transportSettings{TransportConfig: DefaultTransportConfig}
+
+	if _, err := cache.get("http-conf", 1, settings); err != nil {
+		t.Fatalf("get(...): unexpected error: %s", err)
+	}
+	if _, err := cache.get("other-conf", 1, settings); err != nil {
+		t.Fatalf("get(...): unexpected error: %s", err)
+	}
+
+	cache.evict("http-conf")
+
+	for key := range cache.transports {
+		if key.providerConfig == "http-conf" {
+			t.Fatalf("evict(...): transport of the evicted ProviderConfig still cached")
+		}
+	}
+
+	if diff := cmp.Diff(1, len(cache.transports)); diff != "" {
+		t.Fatalf("evict(...): -want cached transports, +got cached transports: %s", diff)
+	}
+}
+
+// Test_transportCacheAlternatingSettings proves that resources of a
+// ProviderConfig with different TLS settings keep their own pooled transport.
+func Test_transportCacheAlternatingSettings(t *testing.T) {
+	cache := newTransportCache()
+	verify := transportSettings{TransportConfig: DefaultTransportConfig}
+	skip := transportSettings{TransportConfig: DefaultTransportConfig, SkipTLSVerify: true}
+
+	want := map[bool]*http.Transport{}
+	for i := 0; i < 4; i++ {
+		settings := verify
+		if i%2 == 1 {
+			settings = skip
+		}
+
+		got, err := cache.get("http-conf", 1, settings)
+		if err != nil {
+			t.Fatalf("get(...): unexpected error: %s", err)
+		}
+
+		if want[settings.SkipTLSVerify] == nil {
+			want[settings.SkipTLSVerify] = got
+		}
+		if got != want[settings.SkipTLSVerify] {
+			t.Fatalf("get(...): call %d with skipTLSVerify %t built a new transport", i, settings.SkipTLSVerify)
+		}
+	}
+
+	if want[true] == want[false] {
+		t.Fatalf("get(...): want a transport per settings")
+	}
+}
+
+func Test_transportCacheBounded(t *testing.T) {
+	cache := newTransportCache()
+	settings := func(i int) transportSettings {
+		return transportSettings{TLSConfigData: TLSConfigData{ServerName: fmt.Sprintf("api-%d.example.com", i)}}
+	}
+
+	first, err := cache.get("http-conf", 1, settings(0))
+	if err != nil {
+		t.Fatalf("get(...): unexpected error: %s", err)
+	}
+	for i := 1; i <= maxTransportsPerProviderConfig; i++ {
+		if _, err := cache.get("http-conf", 1, settings(i)); err != nil {
+			t.Fatalf("get(...): unexpected error: %s", err)
+		}
+	}
+
+	if diff := cmp.Diff(maxTransportsPerProviderConfig, len(cache.transports)); diff != "" {
+		t.Fatalf("get(...): -want cached transports, +got cached transports: %s", diff)
+	}
+
+	// The least recently used transport was closed.
+	again, err := cache.get("http-conf", 1, settings(0))
+	if err != nil {
+		t.Fatalf("get(...): unexpected error: %s", err)
+	}
+	if again == first {
+		t.Fatalf("get(...): want the least recently used transport evicted")
+	}
+}
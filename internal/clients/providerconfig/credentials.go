@@ -0,0 +1,98 @@
+package providerconfig
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errMissingFsPath       = "credentials source Filesystem requires fs.path to be set"
+	errUnsupportedIdentity = "credentials source InjectedIdentity only supports auth type Bearer, got %s"
+	errEmptyCredentials    = "credentials of source %s are empty"
+	errExtractCredentials  = "cannot extract credentials"
+	errParseCredentials    = "cannot parse credentials as a JSON object of strings"
+	errMissingCredsField   = "field %s not found in credentials"
+
+	// serviceAccountTokenPath is where the token of the provider pod's
+	// service account is mounted.
+	serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" // #nosec G101 -- not a credential.
+)
+
+// isTokenFileSource reports whether the credentials of source are a token
+// file sent as bearer token.
+func isTokenFileSource(source xpv1.CredentialsSource) bool {
+	return source == xpv1.CredentialsSourceInjectedIdentity || source == xpv1.CredentialsSourceFilesystem
+}
+
+// newTokenFileAuthenticator returns an Authenticator sending the token file
+// of the credentials: the service account token of the provider for
+// InjectedIdentity, the file at fs.path for Filesystem.
+func newTokenFileAuthenticator(creds apisv1alpha1.ProviderCredentials) (httpClient.Authenticator, error) {
+	if creds.Source == xpv1.CredentialsSourceInjectedIdentity {
+		return httpClient.NewTokenFileAuthenticator(serviceAccountTokenPath), nil
+	}
+
+	if creds.Fs == nil || creds.Fs.Path == "" {
+		return nil, errors.New(errMissingFsPath)
+	}
+
+	return httpClient.NewTokenFileAuthenticator(creds.Fs.Path), nil
+}
+
+// credentialsValue returns the credentials selected by the common credential
+// selectors of the given source: the secretRef key, the env variable or the
+// fs path.
+func credentialsValue(ctx context.Context, kube client.Client, creds apisv1alpha1.ProviderCredentials) (string, error) {
+	if creds.Source == xpv1.CredentialsSourceInjectedIdentity {
+		return "", errors.Errorf(errUnsupportedIdentity, creds.Auth.Type)
+	}
+
+	value, err := resource.CommonCredentialExtractor(ctx, creds.Source, kube, creds.CommonCredentialSelectors)
+	if err != nil {
+		return "", errors.Wrap(err, errExtractCredentials)
+	}
+
+	if len(value) == 0 {
+		if creds.Source == xpv1.CredentialsSourceSecret {
+			return "", errors.Errorf(errMissingSecretKey, creds.SecretRef.Key)
+		}
+		return "", errors.Errorf(errEmptyCredentials, creds.Source)
+	}
+
+	return string(value), nil
+}
+
+// credentialsFields returns the fields of the JSON object held by the
+// credentials, as read by credentialsValue.
+func credentialsFields(ctx context.Context, kube client.Client, creds apisv1alpha1.ProviderCredentials) (map[string]string, error) {
+	value, err := credentialsValue(ctx, kube, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]string{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return nil, errors.Wrap(err, errParseCredentials)
+	}
+
+	return fields, nil
+}
+
+// credentialsField returns the value of the given field of the credentials.
+func credentialsField(fields map[string]string, name string) (string, error) {
+	value, ok := fields[name]
+	if !ok {
+		return "", errors.Errorf(errMissingCredsField, name)
+	}
+
+	return value, nil
+}
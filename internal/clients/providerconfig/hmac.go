@@ -0,0 +1,35 @@
+package providerconfig
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+// Signer builds the HMAC Signer described by signer, reading its key from the
+// referenced Secret. It returns nil if signer is not set.
+func Signer(ctx context.Context, kube client.Client, signer *apisv1alpha1.HMACSigner) (httpClient.Signer, error) {
+	if signer == nil {
+		return nil, nil
+	}
+
+	secret, err := secretKeyValue(ctx, kube, signer.SecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpClient.NewHMACSigner(httpClient.HMACConfig{
+		Algorithm:       signer.Algorithm,
+		Secret:          []byte(secret),
+		Header:          signer.Header,
+		Prefix:          signer.Prefix,
+		StringToSign:    signer.StringToSign,
+		Encoding:        signer.Encoding,
+		TimestampHeader: signer.TimestampHeader,
+		TimestampFormat: signer.TimestampFormat,
+		NonceHeader:     signer.NonceHeader,
+	})
+}
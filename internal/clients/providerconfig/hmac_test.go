@@ -0,0 +1,104 @@
+package providerconfig
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+)
+
+func testHMACSigner(key string) *apisv1alpha1.HMACSigner {
+	return &apisv1alpha1.HMACSigner{
+		SecretRef: xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{
+				Name:      "webhook-secret",
+				Namespace: "crossplane-system",
+			},
+			Key: key,
+		},
+		Header: "X-Signature",
+		Prefix: "sha256=",
+	}
+}
+
+func Test_Signer(t *testing.T) {
+	mac := hmac.New(sha256.New, []byte("s3cr3t"))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	type args struct {
+		kube   client.Client
+		signer *apisv1alpha1.HMACSigner
+	}
+	type want struct {
+		header string
+		err    error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NotSet": {
+			args: args{},
+			want: want{},
+		},
+		"Signer": {
+			args: args{
+				kube:   &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				signer: testHMACSigner("password"),
+			},
+			want: want{
+				header: signature,
+			},
+		},
+		"MissingSecretKey": {
+			args: args{
+				kube:   &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				signer: testHMACSigner("hmac"),
+			},
+			want: want{
+				err: errors.Errorf(errMissingSecretKey, "hmac"),
+			},
+		},
+		"GetSecretFailed": {
+			args: args{
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				signer: testHMACSigner("password"),
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCredentialsSecret),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, gotErr := Signer(context.Background(), tc.args.kube, tc.args.signer)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("Signer(...): -want error, +got error: %s", diff)
+			}
+
+			if s == nil {
+				return
+			}
+
+			request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			if err := s.Sign(request); err != nil {
+				t.Fatalf("Sign(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want.header, request.Header.Get("X-Signature")); diff != "" {
+				t.Fatalf("Signer(...): -want signature header, +got signature header: %s", diff)
+			}
+		})
+	}
+}
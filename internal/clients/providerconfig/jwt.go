@@ -0,0 +1,41 @@
+package providerconfig
+
+import (
+	"github.com/pkg/errors"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errMissingJWTConfig = "auth type JWT requires the jwt block to be set"
+)
+
+func newJWTAuthenticator(name string, config *apisv1alpha1.JWTAuth, privateKey string) (httpClient.Authenticator, error) {
+	if config == nil {
+		return nil, errors.New(errMissingJWTConfig)
+	}
+
+	jwtConfig := httpClient.JWTConfig{
+		Algorithm:  coalesce(config.Algorithm, "RS256"),
+		PrivateKey: []byte(privateKey),
+		KeyID:      config.KeyID,
+		Issuer:     config.Issuer,
+		Subject:    config.Subject,
+		Audience:   config.Audience,
+		Scopes:     config.Scopes,
+	}
+
+	if config.Lifetime != nil {
+		jwtConfig.Lifetime = config.Lifetime.Duration
+	}
+	if config.RefreshBefore != nil {
+		jwtConfig.RefreshBefore = config.RefreshBefore.Duration
+	}
+	if exchange := config.TokenExchange; exchange != nil {
+		jwtConfig.TokenURL = exchange.URL
+		jwtConfig.ExchangeStyle = string(exchange.Style)
+	}
+
+	return httpClient.NewJWTAuthenticator(name, jwtConfig)
+}
@@ -0,0 +1,325 @@
+package providerconfig
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errGetCredentialsSecret = "cannot get credentials secret"
+	errMissingSecretKey     = "key %s not found in credentials secret"
+	errMissingAPIKeyConfig  = "auth type APIKey requires the apiKey block to be set"
+	errMissingOAuth2Config  = "auth type OAuth2 requires the oauth2 block to be set"
+	errUnknownAuthType      = "unknown auth type: %s"
+
+	defaultUsernameKey = "username"
+	defaultPasswordKey = "password"
+
+	defaultFailureThreshold = 5
+	defaultOpenDuration     = 30 * time.Second
+	defaultHalfOpenRequests = 1
+)
+
+// ClientOptions resolves the settings of the given ProviderConfig into
+// options for the Http client. tlsConfig holds the TLS settings of the
+// managed resource, which override those of the ProviderConfig.
+func ClientOptions(ctx context.Context, kube client.Client, pc *apisv1alpha1.ProviderConfig, tlsConfig *apisv1alpha1.TLSConfig) ([]httpClient.Option, error) {
+	opts := []httpClient.Option{
+		httpClient.WithProviderConfig(pc.Name, pc.Generation),
+		httpClient.WithTransportConfig(transportConfig(pc.Spec.ConnectionPool)),
+		httpClient.WithRetryPolicy(applyRetryPolicy(httpClient.DefaultRetryPolicy, pc.Spec.Retry)),
+		httpClient.WithRateLimits(rateLimits(pc.Spec.RateLimits)),
+		httpClient.WithCircuitBreaker(circuitBreakerConfig(pc.Spec.CircuitBreaker)),
+		httpClient.WithRedaction(redactionConfig(pc.Spec.Redaction)),
+	}
+
+	authenticator, err := newAuthenticator(ctx, kube, pc.Name, pc.Spec.Credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	if authenticator != nil {
+		opts = append(opts, httpClient.WithAuthenticator(authenticator))
+	}
+
+	signer, err := Signer(ctx, kube, pc.Spec.HMACSigner)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer != nil {
+		opts = append(opts, httpClient.WithSigner(signer))
+	}
+
+	if tlsConfig := mergeTLSConfig(pc.Spec.TLSConfig, tlsConfig); tlsConfig != nil {
+		data, err := newTLSConfigData(ctx, kube, tlsConfig)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, httpClient.WithTLSConfigData(data))
+	}
+
+	if pc.Spec.Proxy != nil {
+		proxyConfig, err := newProxyConfig(ctx, kube, pc.Spec.Proxy)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, httpClient.WithProxyConfig(proxyConfig))
+	}
+
+	return opts, nil
+}
+
+// transportConfig returns the connection pool settings, falling back to the
+// defaults for the fields that are not set.
+func transportConfig(pool *apisv1alpha1.ConnectionPool) httpClient.TransportConfig {
+	config := httpClient.DefaultTransportConfig
+	if pool == nil {
+		return config
+	}
+
+	if pool.MaxIdleConns != nil {
+		config.MaxIdleConns = *pool.MaxIdleConns
+	}
+	if pool.MaxIdleConnsPerHost != nil {
+		config.MaxIdleConnsPerHost = *pool.MaxIdleConnsPerHost
+	}
+	if pool.IdleConnTimeout != nil {
+		config.IdleConnTimeout = pool.IdleConnTimeout.Duration
+	}
+
+	return config
+}
+
+// rateLimits returns the limits of the requests sent to each host.
+func rateLimits(limits []apisv1alpha1.HostRateLimit) []httpClient.HostRateLimit {
+	if len(limits) == 0 {
+		return nil
+	}
+
+	result := make([]httpClient.HostRateLimit, len(limits))
+	for i, limit := range limits {
+		result[i] = httpClient.HostRateLimit{Host: limit.Host}
+		if limit.RequestsPerSecond != nil {
+			result[i].RequestsPerSecond = *limit.RequestsPerSecond
+		}
+		if limit.Burst != nil {
+			result[i].Burst = *limit.Burst
+		}
+		if limit.MaxInFlight != nil {
+			result[i].MaxInFlight = *limit.MaxInFlight
+		}
+	}
+
+	return result
+}
+
+// circuitBreakerConfig returns the circuit breaker settings, falling back to
+// the defaults for the fields that are not set. The breaker is disabled if
+// breaker is nil.
+func circuitBreakerConfig(breaker *apisv1alpha1.CircuitBreaker) httpClient.CircuitBreakerConfig {
+	if breaker == nil {
+		return httpClient.CircuitBreakerConfig{}
+	}
+
+	config := httpClient.CircuitBreakerConfig{
+		FailureThreshold: defaultFailureThreshold,
+		OpenDuration:     defaultOpenDuration,
+		HalfOpenRequests: defaultHalfOpenRequests,
+	}
+
+	if breaker.FailureThreshold != nil {
+		config.FailureThreshold = *breaker.FailureThreshold
+	}
+	if breaker.OpenDuration != nil {
+		config.OpenDuration = breaker.OpenDuration.Duration
+	}
+	if breaker.HalfOpenRequests != nil {
+		config.HalfOpenRequests = *breaker.HalfOpenRequests
+	}
+
+	return config
+}
+
+// redactionConfig returns the values hidden from the logs in addition to the
+// default ones.
+func redactionConfig(redaction *apisv1alpha1.Redaction) httpClient.RedactionConfig {
+	if redaction == nil {
+		return httpClient.RedactionConfig{}
+	}
+
+	return httpClient.RedactionConfig{
+		Headers:   redaction.Headers,
+		BodyPaths: redaction.BodyPaths,
+	}
+}
+
+// StatusMasker returns the masker hiding the values of all the rules from the
+// status of a resource, typically those of its ProviderConfig and its own. It
+// returns nil if no rule is set.
+func StatusMasker(rules ...*apisv1alpha1.StatusMasking) (*httpClient.Redactor, error) {
+	var config httpClient.RedactionConfig
+	for _, r := range rules {
+		if r == nil {
+			continue
+		}
+		config.Headers = append(config.Headers, r.Headers...)
+		config.BodyPaths = append(config.BodyPaths, r.BodyPaths...)
+	}
+
+	if len(config.Headers) == 0 && len(config.BodyPaths) == 0 {
+		return nil, nil
+	}
+
+	return httpClient.NewMasker(config)
+}
+
+// newAuthenticator builds the Authenticator described by the credentials auth
+// block, reading its values from the credentials source. It returns nil if no
+// auth block is set, whatever the credentials source, so requests are only
+// authenticated on demand.
+func newAuthenticator(ctx context.Context, kube client.Client, name string, creds apisv1alpha1.ProviderCredentials) (httpClient.Authenticator, error) {
+	if creds.Auth == nil {
+		return nil, nil
+	}
+
+	authType := creds.Auth.Type
+	switch authType {
+	case apisv1alpha1.AuthTypeOAuth2:
+		return newOAuth2Authenticator(ctx, kube, name, creds.Auth.OAuth2)
+	case apisv1alpha1.AuthTypeSigV4:
+		return newSigV4Authenticator(ctx, kube, creds)
+	case apisv1alpha1.AuthTypeBasic:
+		return newBasicAuthenticator(ctx, kube, creds)
+	case apisv1alpha1.AuthTypeBearer:
+		if isTokenFileSource(creds.Source) {
+			return newTokenFileAuthenticator(creds)
+		}
+	}
+
+	value, err := credentialsValue(ctx, kube, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	switch authType {
+	case apisv1alpha1.AuthTypeBearer:
+		return httpClient.NewBearerAuthenticator(value), nil
+	case apisv1alpha1.AuthTypeAPIKey:
+		if creds.Auth.APIKey == nil {
+			return nil, errors.New(errMissingAPIKeyConfig)
+		}
+
+		if creds.Auth.APIKey.In == apisv1alpha1.APIKeyInQuery {
+			return httpClient.NewAPIKeyQueryAuthenticator(creds.Auth.APIKey.Name, value), nil
+		}
+
+		return httpClient.NewAPIKeyHeaderAuthenticator(creds.Auth.APIKey.Name, value), nil
+	case apisv1alpha1.AuthTypeJWT:
+		return newJWTAuthenticator(name, creds.Auth.JWT, value)
+	default:
+		return nil, errors.Errorf(errUnknownAuthType, creds.Auth.Type)
+	}
+}
+
+func newBasicAuthenticator(ctx context.Context, kube client.Client, creds apisv1alpha1.ProviderCredentials) (httpClient.Authenticator, error) {
+	fields, err := credentialsFields(ctx, kube, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	usernameKey, passwordKey := defaultUsernameKey, defaultPasswordKey
+	if basic := creds.Auth.Basic; basic != nil {
+		usernameKey = coalesce(basic.UsernameKey, defaultUsernameKey)
+		passwordKey = coalesce(basic.PasswordKey, defaultPasswordKey)
+	}
+
+	username, err := credentialsField(fields, usernameKey)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := credentialsField(fields, passwordKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpClient.NewBasicAuthenticator(username, password), nil
+}
+
+func newOAuth2Authenticator(ctx context.Context, kube client.Client, name string, config *apisv1alpha1.OAuth2Auth) (httpClient.Authenticator, error) {
+	if config == nil {
+		return nil, errors.New(errMissingOAuth2Config)
+	}
+
+	clientID, err := secretKeyValue(ctx, kube, config.ClientIDSecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	clientSecret, err := secretKeyValue(ctx, kube, config.ClientSecretSecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	oauth2Config := httpClient.OAuth2Config{
+		TokenURL:     config.TokenURL,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       config.Scopes,
+		Audience:     config.Audience,
+	}
+
+	if config.RefreshBefore != nil {
+		oauth2Config.RefreshBefore = config.RefreshBefore.Duration
+	}
+
+	return httpClient.NewOAuth2Authenticator(name, oauth2Config), nil
+}
+
+func getSecret(ctx context.Context, kube client.Client, ref xpv1.SecretReference) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return nil, errors.Wrap(err, errGetCredentialsSecret)
+	}
+
+	return secret, nil
+}
+
+// secretKeyValue returns the value of the Secret key selected by ref.
+func secretKeyValue(ctx context.Context, kube client.Client, ref xpv1.SecretKeySelector) (string, error) {
+	secret, err := getSecret(ctx, kube, ref.SecretReference)
+	if err != nil {
+		return "", err
+	}
+
+	return secretValue(secret, ref.Key)
+}
+
+func secretValue(secret *corev1.Secret, key string) (string, error) {
+	value, ok := secret.Data[key]
+	if !ok {
+		return "", errors.Errorf(errMissingSecretKey, key)
+	}
+
+	return string(value), nil
+}
+
+func coalesce(value, fallback string) string {
+	if value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,488 @@
+package providerconfig
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+var (
+	errBoom = errors.New("boom")
+)
+
+const (
+	testProviderConfigName = "http-conf"
+)
+
+var testSecretData = map[string][]byte{
+	"username":    []byte("john_doe"),
+	"password":    []byte("s3cr3t"),
+	"token":       []byte("my-token"),
+	"credentials": []byte(`{"username":"john_doe","password":"s3cr3t","accessKeyId":"AKID","secretAccessKey":"s3cr3t"}`),
+}
+
+func testCredentials(auth *apisv1alpha1.Auth) apisv1alpha1.ProviderCredentials {
+	return testCredentialsKey("token", auth)
+}
+
+func testCredentialsKey(key string, auth *apisv1alpha1.Auth) apisv1alpha1.ProviderCredentials {
+	return apisv1alpha1.ProviderCredentials{
+		Source: xpv1.CredentialsSourceSecret,
+		CommonCredentialSelectors: xpv1.CommonCredentialSelectors{
+			SecretRef: &xpv1.SecretKeySelector{
+				SecretReference: xpv1.SecretReference{
+					Name:      "http-creds",
+					Namespace: "crossplane-system",
+				},
+				Key: key,
+			},
+		},
+		Auth: auth,
+	}
+}
+
+func mockSecretGet(data map[string][]byte) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		if s, ok := obj.(*corev1.Secret); ok {
+			s.Data = data
+		}
+		return nil
+	}
+}
+
+func Test_newAuthenticator(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("projected-token\n"), 0o600); err != nil {
+		t.Fatalf("cannot write token file: %s", err)
+	}
+	credentialsFile := filepath.Join(dir, "credentials")
+	if err := os.WriteFile(credentialsFile, testSecretData["credentials"], 0o600); err != nil {
+		t.Fatalf("cannot write credentials file: %s", err)
+	}
+
+	envCredentials := func(name string, auth *apisv1alpha1.Auth) apisv1alpha1.ProviderCredentials {
+		return apisv1alpha1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceEnvironment,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{Env: &xpv1.EnvSelector{Name: name}},
+			Auth:                      auth,
+		}
+	}
+	fsCredentials := func(path string, auth *apisv1alpha1.Auth) apisv1alpha1.ProviderCredentials {
+		return apisv1alpha1.ProviderCredentials{
+			Source:                    xpv1.CredentialsSourceFilesystem,
+			CommonCredentialSelectors: xpv1.CommonCredentialSelectors{Fs: &xpv1.FsSelector{Path: path}},
+			Auth:                      auth,
+		}
+	}
+	sigv4 := &apisv1alpha1.SigV4Auth{Region: "eu-west-1", Service: "execute-api"}
+
+	type args struct {
+		kube  client.Client
+		env   map[string]string
+		creds apisv1alpha1.ProviderCredentials
+	}
+	type want struct {
+		header string
+		signed bool
+		query  string
+		err    error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoCredentials": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceNone},
+			},
+			want: want{},
+		},
+		"SecretNoAuth": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(nil),
+			},
+			want: want{},
+		},
+		"EnvironmentNoAuth": {
+			args: args{
+				env:   map[string]string{"HTTP_TOKEN": "env-token"},
+				creds: envCredentials("HTTP_TOKEN", nil),
+			},
+			want: want{},
+		},
+		"InjectedIdentityNoAuth": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{Source: xpv1.CredentialsSourceInjectedIdentity},
+			},
+			want: want{},
+		},
+		"EnvironmentBearer": {
+			args: args{
+				env:   map[string]string{"HTTP_TOKEN": "env-token"},
+				creds: envCredentials("HTTP_TOKEN", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				header: "Bearer env-token",
+			},
+		},
+		"FilesystemBearer": {
+			args: args{
+				creds: fsCredentials(tokenFile, &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				header: "Bearer projected-token",
+			},
+		},
+		"UnsupportedSource": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceInjectedIdentity,
+					Auth: &apisv1alpha1.Auth{
+						Type:   apisv1alpha1.AuthTypeAPIKey,
+						APIKey: &apisv1alpha1.APIKeyAuth{Name: "X-API-Key"},
+					},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errUnsupportedIdentity, apisv1alpha1.AuthTypeAPIKey),
+			},
+		},
+		"SecretNotFound": {
+			args: args{
+				kube:  &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				err: errors.Wrap(errors.Wrap(errBoom, errGetCredentialsSecret), errExtractCredentials),
+			},
+		},
+		"BearerMissingKey": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(map[string][]byte{"username": []byte("john_doe")})},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				err: errors.Errorf(errMissingSecretKey, "token"),
+			},
+		},
+		"EnvironmentAPIKey": {
+			args: args{
+				env: map[string]string{"HTTP_API_KEY": "env-key"},
+				creds: envCredentials("HTTP_API_KEY", &apisv1alpha1.Auth{
+					Type:   apisv1alpha1.AuthTypeAPIKey,
+					APIKey: &apisv1alpha1.APIKeyAuth{In: apisv1alpha1.APIKeyInQuery, Name: "api_key"},
+				}),
+			},
+			want: want{
+				query: "api_key=env-key",
+			},
+		},
+		"EnvironmentEmpty": {
+			args: args{
+				creds: envCredentials("HTTP_UNSET_TOKEN", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				err: errors.Errorf(errEmptyCredentials, xpv1.CredentialsSourceEnvironment),
+			},
+		},
+		"FilesystemNoAuth": {
+			args: args{
+				creds: fsCredentials(tokenFile, nil),
+			},
+			want: want{},
+		},
+		"FilesystemMissingPath": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceFilesystem,
+					Auth:   &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer},
+				},
+			},
+			want: want{
+				err: errors.New(errMissingFsPath),
+			},
+		},
+		"Basic": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentialsKey("credentials", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBasic}),
+			},
+			want: want{
+				header: "Basic am9obl9kb2U6czNjcjN0",
+			},
+		},
+		"BasicEnvironment": {
+			args: args{
+				env:   map[string]string{"HTTP_CREDENTIALS": string(testSecretData["credentials"])},
+				creds: envCredentials("HTTP_CREDENTIALS", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBasic}),
+			},
+			want: want{
+				header: "Basic am9obl9kb2U6czNjcjN0",
+			},
+		},
+		"BasicFilesystem": {
+			args: args{
+				creds: fsCredentials(credentialsFile, &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBasic}),
+			},
+			want: want{
+				header: "Basic am9obl9kb2U6czNjcjN0",
+			},
+		},
+		"BasicInjectedIdentity": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceInjectedIdentity,
+					Auth:   &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBasic},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errUnsupportedIdentity, apisv1alpha1.AuthTypeBasic),
+			},
+		},
+		"BasicNotJSON": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBasic}),
+			},
+			want: want{
+				err: errors.Wrap(json.Unmarshal([]byte("my-token"), &map[string]string{}), errParseCredentials),
+			},
+		},
+		"BasicMissingKey": {
+			args: args{
+				kube: &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentialsKey("credentials", &apisv1alpha1.Auth{
+					Type:  apisv1alpha1.AuthTypeBasic,
+					Basic: &apisv1alpha1.BasicAuth{UsernameKey: "user"},
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errMissingCredsField, "user"),
+			},
+		},
+		"Bearer": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeBearer}),
+			},
+			want: want{
+				header: "Bearer my-token",
+			},
+		},
+		"APIKeyQuery": {
+			args: args{
+				kube: &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(&apisv1alpha1.Auth{
+					Type:   apisv1alpha1.AuthTypeAPIKey,
+					APIKey: &apisv1alpha1.APIKeyAuth{In: apisv1alpha1.APIKeyInQuery, Name: "api_key"},
+				}),
+			},
+			want: want{
+				query: "api_key=my-token",
+			},
+		},
+		"OAuth2MissingConfig": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceNone,
+					Auth:   &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeOAuth2},
+				},
+			},
+			want: want{
+				err: errors.New(errMissingOAuth2Config),
+			},
+		},
+		"OAuth2ClientSecretNotFound": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceNone,
+					Auth: &apisv1alpha1.Auth{
+						Type: apisv1alpha1.AuthTypeOAuth2,
+						OAuth2: &apisv1alpha1.OAuth2Auth{
+							TokenURL: "https://auth.example.com/token",
+						},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCredentialsSecret),
+			},
+		},
+		"SigV4MissingConfig": {
+			args: args{
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4}),
+			},
+			want: want{
+				err: errors.New(errMissingSigV4Config),
+			},
+		},
+		"SigV4": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentialsKey("credentials", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4, SigV4: sigv4}),
+			},
+			want: want{
+				signed: true,
+			},
+		},
+		"SigV4Environment": {
+			args: args{
+				env:   map[string]string{"HTTP_CREDENTIALS": string(testSecretData["credentials"])},
+				creds: envCredentials("HTTP_CREDENTIALS", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4, SigV4: sigv4}),
+			},
+			want: want{
+				signed: true,
+			},
+		},
+		"SigV4Filesystem": {
+			args: args{
+				creds: fsCredentials(credentialsFile, &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4, SigV4: sigv4}),
+			},
+			want: want{
+				signed: true,
+			},
+		},
+		"SigV4InjectedIdentity": {
+			args: args{
+				creds: apisv1alpha1.ProviderCredentials{
+					Source: xpv1.CredentialsSourceInjectedIdentity,
+					Auth:   &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4, SigV4: sigv4},
+				},
+			},
+			want: want{
+				err: errors.Errorf(errUnsupportedIdentity, apisv1alpha1.AuthTypeSigV4),
+			},
+		},
+		"SigV4MissingAccessKey": {
+			args: args{
+				env:   map[string]string{"HTTP_CREDENTIALS": `{"secretAccessKey":"s3cr3t"}`},
+				creds: envCredentials("HTTP_CREDENTIALS", &apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeSigV4, SigV4: sigv4}),
+			},
+			want: want{
+				err: errors.Errorf(errMissingCredsField, defaultAccessKeyIDKey),
+			},
+		},
+		"JWTMissingConfig": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeJWT}),
+			},
+			want: want{
+				err: errors.New(errMissingJWTConfig),
+			},
+		},
+		"JWTMissingPrivateKey": {
+			args: args{
+				kube: &test.MockClient{MockGet: mockSecretGet(map[string][]byte{"username": []byte("john_doe")})},
+				creds: testCredentials(&apisv1alpha1.Auth{
+					Type: apisv1alpha1.AuthTypeJWT,
+					JWT:  &apisv1alpha1.JWTAuth{Issuer: "123456"},
+				}),
+			},
+			want: want{
+				err: errors.Errorf(errMissingSecretKey, "token"),
+			},
+		},
+		"APIKeyMissingConfig": {
+			args: args{
+				kube:  &test.MockClient{MockGet: mockSecretGet(testSecretData)},
+				creds: testCredentials(&apisv1alpha1.Auth{Type: apisv1alpha1.AuthTypeAPIKey}),
+			},
+			want: want{
+				err: errors.New(errMissingAPIKeyConfig),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range tc.args.env {
+				t.Setenv(k, v)
+			}
+
+			a, gotErr := newAuthenticator(context.Background(), tc.args.kube, testProviderConfigName, tc.args.creds)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("newAuthenticator(...): -want error, +got error: %s", diff)
+			}
+
+			if gotErr != nil {
+				return
+			}
+
+			request, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+			if a != nil {
+				if err := a.Authenticate(context.Background(), request); err != nil {
+					t.Fatalf("Authenticate(...): unexpected error: %s", err)
+				}
+			}
+
+			if tc.want.signed {
+				// SigV4 signatures depend on the time of the request.
+				if got := request.Header.Get("Authorization"); !strings.HasPrefix(got, "AWS4-HMAC-SHA256 Credential=AKID/") {
+					t.Fatalf("newAuthenticator(...): want a SigV4 authorization header, got %q", got)
+				}
+				return
+			}
+
+			if diff := cmp.Diff(tc.want.header, request.Header.Get("Authorization")); diff != "" {
+				t.Fatalf("newAuthenticator(...): -want authorization header, +got authorization header: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.query, request.URL.RawQuery); diff != "" {
+				t.Fatalf("newAuthenticator(...): -want query, +got query: %s", diff)
+			}
+		})
+	}
+}
+
+// Test_ClientOptionsInjectedIdentity proves that a ProviderConfig without an
+// auth block sends no credentials, even when its source has some.
+func Test_ClientOptionsInjectedIdentity(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Values("Authorization")
+	}))
+	defer server.Close()
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	pc.Name = "injected-identity"
+	pc.Spec.Credentials.Source = xpv1.CredentialsSourceInjectedIdentity
+
+	opts, err := ClientOptions(context.Background(), &test.MockClient{}, pc, nil)
+	if err != nil {
+		t.Fatalf("ClientOptions(...): unexpected error: %s", err)
+	}
+
+	c, err := httpClient.NewClient(logging.NewNopLogger(), time.Minute, opts...)
+	if err != nil {
+		t.Fatalf("NewClient(...): unexpected error: %s", err)
+	}
+
+	if _, err := c.SendRequest(context.Background(), http.MethodGet, server.URL, "", nil, false); err != nil {
+		t.Fatalf("SendRequest(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff([]string(nil), got); diff != "" {
+		t.Fatalf("SendRequest(...): -want authorization header, +got authorization header: %s", diff)
+	}
+}
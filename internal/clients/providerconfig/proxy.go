@@ -0,0 +1,39 @@
+package providerconfig
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+// newProxyConfig resolves the proxy settings, reading the proxy credentials
+// from the referenced Secret.
+func newProxyConfig(ctx context.Context, kube client.Client, proxy *apisv1alpha1.ProxyConfig) (httpClient.ProxyConfig, error) {
+	config := httpClient.ProxyConfig{
+		URL:             proxy.URL,
+		NoProxy:         proxy.NoProxy,
+		FromEnvironment: proxy.FromEnvironment,
+	}
+
+	if proxy.CredentialsSecretRef == nil {
+		return config, nil
+	}
+
+	secret, err := getSecret(ctx, kube, *proxy.CredentialsSecretRef)
+	if err != nil {
+		return httpClient.ProxyConfig{}, err
+	}
+
+	if config.Username, err = secretValue(secret, defaultUsernameKey); err != nil {
+		return httpClient.ProxyConfig{}, err
+	}
+
+	if config.Password, err = secretValue(secret, defaultPasswordKey); err != nil {
+		return httpClient.ProxyConfig{}, err
+	}
+
+	return config, nil
+}
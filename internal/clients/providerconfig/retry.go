@@ -0,0 +1,55 @@
+package providerconfig
+
+import (
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+// RetryOverride returns the override applying the given retry policy of a
+// mapping over the one of the ProviderConfig, or nil if it is not set.
+func RetryOverride(policy *apisv1alpha1.RetryPolicy) httpClient.RetryOverride {
+	if policy == nil {
+		return nil
+	}
+
+	return func(base httpClient.RetryPolicy) httpClient.RetryPolicy {
+		return applyRetryPolicy(base, policy)
+	}
+}
+
+// applyRetryPolicy returns base with the fields set in policy overridden.
+func applyRetryPolicy(base httpClient.RetryPolicy, policy *apisv1alpha1.RetryPolicy) httpClient.RetryPolicy {
+	if policy == nil {
+		return base
+	}
+
+	if policy.MaxAttempts != nil {
+		base.MaxAttempts = *policy.MaxAttempts
+	}
+	if policy.BaseBackoff != nil {
+		base.BaseBackoff = policy.BaseBackoff.Duration
+	}
+	if policy.MaxBackoff != nil {
+		base.MaxBackoff = policy.MaxBackoff.Duration
+	}
+	if policy.Jitter != nil {
+		base.Jitter = *policy.Jitter
+	}
+	if policy.RetryableMethods != nil {
+		base.RetryableMethods = policy.RetryableMethods
+	}
+	if policy.RetryableStatusCodes != nil {
+		base.RetryableStatusCodes = policy.RetryableStatusCodes
+	}
+	if policy.RetryableNetworkErrors != nil {
+		base.RetryableNetworkErrors = make([]httpClient.NetworkError, len(policy.RetryableNetworkErrors))
+		for i, e := range policy.RetryableNetworkErrors {
+			base.RetryableNetworkErrors[i] = httpClient.NetworkError(e)
+		}
+	}
+	if policy.HonorRetryAfter != nil {
+		base.HonorRetryAfter = *policy.HonorRetryAfter
+	}
+
+	return base
+}
@@ -0,0 +1,70 @@
+package providerconfig
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+func Test_applyRetryPolicy(t *testing.T) {
+	maxAttempts := 5
+	jitter := false
+
+	type args struct {
+		base   httpClient.RetryPolicy
+		policy *apisv1alpha1.RetryPolicy
+	}
+	cases := map[string]struct {
+		args args
+		want httpClient.RetryPolicy
+	}{
+		"NoPolicy": {
+			args: args{
+				base: httpClient.DefaultRetryPolicy,
+			},
+			want: httpClient.DefaultRetryPolicy,
+		},
+		"PartialOverride": {
+			args: args{
+				base: httpClient.RetryPolicy{
+					MaxAttempts:          3,
+					BaseBackoff:          200 * time.Millisecond,
+					MaxBackoff:           5 * time.Second,
+					Jitter:               true,
+					RetryableStatusCodes: []int{502},
+					HonorRetryAfter:      true,
+				},
+				policy: &apisv1alpha1.RetryPolicy{
+					MaxAttempts:            &maxAttempts,
+					MaxBackoff:             &metav1.Duration{Duration: 30 * time.Second},
+					Jitter:                 &jitter,
+					RetryableMethods:       []string{"GET", "POST"},
+					RetryableNetworkErrors: []apisv1alpha1.NetworkError{apisv1alpha1.NetworkErrorTimeout},
+				},
+			},
+			want: httpClient.RetryPolicy{
+				MaxAttempts:            5,
+				BaseBackoff:            200 * time.Millisecond,
+				MaxBackoff:             30 * time.Second,
+				Jitter:                 false,
+				RetryableMethods:       []string{"GET", "POST"},
+				RetryableStatusCodes:   []int{502},
+				RetryableNetworkErrors: []httpClient.NetworkError{httpClient.NetworkErrorTimeout},
+				HonorRetryAfter:        true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := applyRetryPolicy(tc.args.base, tc.args.policy)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Fatalf("applyRetryPolicy(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package providerconfig
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errMissingSigV4Config = "auth type SigV4 requires the sigv4 block to be set"
+
+	defaultAccessKeyIDKey     = "accessKeyId"
+	defaultSecretAccessKeyKey = "secretAccessKey"
+	defaultSessionTokenKey    = "sessionToken"
+)
+
+func newSigV4Authenticator(ctx context.Context, kube client.Client, creds apisv1alpha1.ProviderCredentials) (httpClient.Authenticator, error) {
+	sigv4 := creds.Auth.SigV4
+	if sigv4 == nil {
+		return nil, errors.New(errMissingSigV4Config)
+	}
+
+	fields, err := credentialsFields(ctx, kube, creds)
+	if err != nil {
+		return nil, err
+	}
+
+	config := httpClient.SigV4Config{
+		Region:       sigv4.Region,
+		Service:      sigv4.Service,
+		SessionToken: fields[coalesce(sigv4.SessionTokenKey, defaultSessionTokenKey)],
+	}
+
+	if config.AccessKeyID, err = credentialsField(fields, coalesce(sigv4.AccessKeyIDKey, defaultAccessKeyIDKey)); err != nil {
+		return nil, err
+	}
+	if config.SecretAccessKey, err = credentialsField(fields, coalesce(sigv4.SecretAccessKeyKey, defaultSecretAccessKeyKey)); err != nil {
+		return nil, err
+	}
+
+	return httpClient.NewSigV4Authenticator(config), nil
+}
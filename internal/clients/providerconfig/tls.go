@@ -0,0 +1,122 @@
+package providerconfig
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errGetCABundleConfigMap = "cannot get CA bundle config map"
+	errMissingConfigMapKey  = "key %s not found in config map %s/%s"
+	errUnknownTLSVersion    = "unknown TLS version: %s"
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// mergeTLSConfig returns the TLS settings of the ProviderConfig overridden by
+// the fields set on the managed resource.
+func mergeTLSConfig(pcConfig, resourceConfig *apisv1alpha1.TLSConfig) *apisv1alpha1.TLSConfig {
+	if resourceConfig == nil {
+		return pcConfig
+	}
+
+	if pcConfig == nil {
+		return resourceConfig
+	}
+
+	merged := pcConfig.DeepCopy()
+	if resourceConfig.CABundle != nil {
+		merged.CABundle = resourceConfig.CABundle
+	}
+	if resourceConfig.ClientCertSecretRef != nil {
+		merged.ClientCertSecretRef = resourceConfig.ClientCertSecretRef
+	}
+	if resourceConfig.ClientKeySecretRef != nil {
+		merged.ClientKeySecretRef = resourceConfig.ClientKeySecretRef
+	}
+	if resourceConfig.MinVersion != "" {
+		merged.MinVersion = resourceConfig.MinVersion
+	}
+	if resourceConfig.ServerName != "" {
+		merged.ServerName = resourceConfig.ServerName
+	}
+
+	return merged
+}
+
+// newTLSConfigData reads the certificates referenced by the TLS settings.
+func newTLSConfigData(ctx context.Context, kube client.Client, config *apisv1alpha1.TLSConfig) (httpClient.TLSConfigData, error) {
+	data := httpClient.TLSConfigData{
+		ServerName: config.ServerName,
+	}
+
+	if config.MinVersion != "" {
+		version, ok := tlsVersions[config.MinVersion]
+		if !ok {
+			return httpClient.TLSConfigData{}, errors.Errorf(errUnknownTLSVersion, config.MinVersion)
+		}
+		data.MinVersion = version
+	}
+
+	if config.CABundle != nil {
+		caBundle, err := caBundleValue(ctx, kube, config.CABundle)
+		if err != nil {
+			return httpClient.TLSConfigData{}, err
+		}
+		data.CABundle = []byte(caBundle)
+	}
+
+	if config.ClientCertSecretRef != nil {
+		clientCert, err := secretKeyValue(ctx, kube, *config.ClientCertSecretRef)
+		if err != nil {
+			return httpClient.TLSConfigData{}, err
+		}
+		data.ClientCert = []byte(clientCert)
+	}
+
+	if config.ClientKeySecretRef != nil {
+		clientKey, err := secretKeyValue(ctx, kube, *config.ClientKeySecretRef)
+		if err != nil {
+			return httpClient.TLSConfigData{}, err
+		}
+		data.ClientKey = []byte(clientKey)
+	}
+
+	return data, nil
+}
+
+func caBundleValue(ctx context.Context, kube client.Client, source *apisv1alpha1.CABundleSource) (string, error) {
+	if source.SecretRef != nil {
+		return secretKeyValue(ctx, kube, *source.SecretRef)
+	}
+
+	if source.ConfigMapRef == nil {
+		return "", nil
+	}
+
+	ref := source.ConfigMapRef
+	cm := &corev1.ConfigMap{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return "", errors.Wrap(err, errGetCABundleConfigMap)
+	}
+
+	value, ok := cm.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf(errMissingConfigMapKey, ref.Key, ref.Namespace, ref.Name)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,148 @@
+package providerconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+func Test_mergeTLSConfig(t *testing.T) {
+	type args struct {
+		pcConfig       *apisv1alpha1.TLSConfig
+		resourceConfig *apisv1alpha1.TLSConfig
+	}
+	type want struct {
+		result *apisv1alpha1.TLSConfig
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"BothNil": {
+			args: args{},
+			want: want{},
+		},
+		"OnlyProviderConfig": {
+			args: args{
+				pcConfig: &apisv1alpha1.TLSConfig{MinVersion: "1.2"},
+			},
+			want: want{
+				result: &apisv1alpha1.TLSConfig{MinVersion: "1.2"},
+			},
+		},
+		"ResourceOverrides": {
+			args: args{
+				pcConfig:       &apisv1alpha1.TLSConfig{MinVersion: "1.2", ServerName: "api.internal"},
+				resourceConfig: &apisv1alpha1.TLSConfig{MinVersion: "1.3"},
+			},
+			want: want{
+				result: &apisv1alpha1.TLSConfig{MinVersion: "1.3", ServerName: "api.internal"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := mergeTLSConfig(tc.args.pcConfig, tc.args.resourceConfig)
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Fatalf("mergeTLSConfig(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_newTLSConfigData(t *testing.T) {
+	type args struct {
+		kube   client.Client
+		config *apisv1alpha1.TLSConfig
+	}
+	type want struct {
+		result httpClient.TLSConfigData
+		err    error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"UnknownVersion": {
+			args: args{
+				config: &apisv1alpha1.TLSConfig{MinVersion: "2.0"},
+			},
+			want: want{
+				err: errors.Errorf(errUnknownTLSVersion, "2.0"),
+			},
+		},
+		"ConfigMapNotFound": {
+			args: args{
+				kube: &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				config: &apisv1alpha1.TLSConfig{
+					CABundle: &apisv1alpha1.CABundleSource{
+						ConfigMapRef: &apisv1alpha1.ConfigMapKeySelector{Name: "ca", Namespace: "crossplane-system", Key: "ca.crt"},
+					},
+				},
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetCABundleConfigMap),
+			},
+		},
+		"Success": {
+			args: args{
+				kube: &test.MockClient{MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+					switch o := obj.(type) {
+					case *corev1.ConfigMap:
+						o.Data = map[string]string{"ca.crt": "ca-pem"}
+					case *corev1.Secret:
+						o.Data = map[string][]byte{"tls.crt": []byte("cert-pem"), "tls.key": []byte("key-pem")}
+					}
+					return nil
+				}},
+				config: &apisv1alpha1.TLSConfig{
+					CABundle: &apisv1alpha1.CABundleSource{
+						ConfigMapRef: &apisv1alpha1.ConfigMapKeySelector{Name: "ca", Namespace: "crossplane-system", Key: "ca.crt"},
+					},
+					ClientCertSecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "client", Namespace: "crossplane-system"},
+						Key:             "tls.crt",
+					},
+					ClientKeySecretRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Name: "client", Namespace: "crossplane-system"},
+						Key:             "tls.key",
+					},
+					MinVersion: "1.3",
+					ServerName: "api.internal",
+				},
+			},
+			want: want{
+				result: httpClient.TLSConfigData{
+					CABundle:   []byte("ca-pem"),
+					ClientCert: []byte("cert-pem"),
+					ClientKey:  []byte("key-pem"),
+					MinVersion: tls.VersionTLS13,
+					ServerName: "api.internal",
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := newTLSConfigData(context.Background(), tc.args.kube, tc.args.config)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("newTLSConfigData(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Fatalf("newTLSConfigData(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errGetProviderConfig    = "cannot get ProviderConfig"
+	errUpdateTrippedHosts   = "cannot update ProviderConfig tripped hosts"
+	errAddCircuitChangesRun = "cannot add circuit changes runnable"
+)
+
+// setupCircuitStatus adds a controller that reports the hosts whose circuit
+// is open in the status of their ProviderConfig.
+func setupCircuitStatus(mgr ctrl.Manager, o controller.Options) error {
+	name := "circuits/" + strings.ToLower(v1alpha1.ProviderConfigGroupKind)
+
+	changes := make(chan event.GenericEvent)
+	if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		return forwardCircuitChanges(ctx, httpClient.CircuitChanges(), changes)
+	})); err != nil {
+		return errors.Wrap(err, errAddCircuitChangesRun)
+	}
+
+	r := &circuitStatusReconciler{
+		kube:    mgr.GetClient(),
+		logger:  o.Logger.WithValues("controller", name),
+		tripped: httpClient.TrippedHosts,
+		now:     time.Now,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ProviderConfig{}).
+		Watches(&source.Channel{Source: changes}, &handler.EnqueueRequestForObject{}).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// forwardCircuitChanges turns the names of the ProviderConfigs whose circuits
+// changed into events enqueuing them.
+func forwardCircuitChanges(ctx context.Context, names <-chan string, events chan<- event.GenericEvent) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case name := <-names:
+			pc := &v1alpha1.ProviderConfig{ObjectMeta: metav1.ObjectMeta{Name: name}}
+			select {
+			case <-ctx.Done():
+				return nil
+			case events <- event.GenericEvent{Object: pc}:
+			}
+		}
+	}
+}
+
+// circuitStatusReconciler keeps the tripped hosts of a ProviderConfig status
+// in sync with its circuit breakers.
+type circuitStatusReconciler struct {
+	kube    client.Client
+	logger  logging.Logger
+	tripped func(providerConfig string) []httpClient.TrippedHost
+	now     func() time.Time
+}
+
+func (r *circuitStatusReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	pc := &v1alpha1.ProviderConfig{}
+	if err := r.kube.Get(ctx, req.NamespacedName, pc); err != nil {
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetProviderConfig)
+	}
+
+	hosts := r.tripped(pc.Name)
+
+	// Open circuits turn half-open without notice, once their open duration
+	// elapsed.
+	result := reconcile.Result{RequeueAfter: untilHalfOpen(hosts, r.now())}
+
+	tripped := trippedHosts(hosts)
+	if equalTrippedHosts(pc.Status.TrippedHosts, tripped) {
+		return result, nil
+	}
+
+	r.logger.Debug("Updating tripped hosts", "providerConfig", pc.Name, "trippedHosts", len(tripped))
+	pc.Status.TrippedHosts = tripped
+	return result, errors.Wrap(r.kube.Status().Update(ctx, pc), errUpdateTrippedHosts)
+}
+
+// untilHalfOpen returns how long until the first of the open circuits turns
+// half-open, or zero if none is open.
+func untilHalfOpen(hosts []httpClient.TrippedHost, now time.Time) time.Duration {
+	var next time.Duration
+	for _, h := range hosts {
+		if h.HalfOpen {
+			continue
+		}
+		if d := h.Until.Sub(now); d > 0 && (next == 0 || d < next) {
+			next = d
+		}
+	}
+	return next
+}
+
+func trippedHosts(hosts []httpClient.TrippedHost) []v1alpha1.TrippedHost {
+	if len(hosts) == 0 {
+		return nil
+	}
+
+	result := make([]v1alpha1.TrippedHost, len(hosts))
+	for i, h := range hosts {
+		// The API server stores times with a second precision.
+		result[i] = v1alpha1.TrippedHost{Host: h.Host, Since: metav1.NewTime(h.Since.Truncate(time.Second)), State: v1alpha1.CircuitStateOpen}
+		if h.HalfOpen {
+			result[i].State = v1alpha1.CircuitStateHalfOpen
+		}
+	}
+	return result
+}
+
+func equalTrippedHosts(a, b []v1alpha1.TrippedHost) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i].Host != b[i].Host || a[i].State != b[i].State || !a[i].Since.Equal(&b[i].Since) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+var (
+	testNow     = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	testTripped = testNow.Add(-10 * time.Second)
+)
+
+func Test_circuitStatusReconciler(t *testing.T) {
+	type args struct {
+		status  []v1alpha1.TrippedHost
+		tripped []httpClient.TrippedHost
+	}
+	type want struct {
+		result  reconcile.Result
+		updated bool
+		status  []v1alpha1.TrippedHost
+		err     error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Tripped": {
+			args: args{
+				tripped: []httpClient.TrippedHost{
+					{Host: "api.example.com", Since: testTripped, Until: testNow.Add(20 * time.Second)},
+				},
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: 20 * time.Second},
+				updated: true,
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+			},
+		},
+		"StillOpen": {
+			args: args{
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+				tripped: []httpClient.TrippedHost{
+					{Host: "api.example.com", Since: testTripped, Until: testNow.Add(5 * time.Second)},
+					{Host: "other.example.com", Since: testTripped, Until: testNow.Add(10 * time.Second)},
+				},
+			},
+			want: want{
+				result:  reconcile.Result{RequeueAfter: 5 * time.Second},
+				updated: true,
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+					{Host: "other.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+			},
+		},
+		"HalfOpen": {
+			args: args{
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+				tripped: []httpClient.TrippedHost{
+					{Host: "api.example.com", Since: testTripped, HalfOpen: true},
+				},
+			},
+			want: want{
+				updated: true,
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateHalfOpen},
+				},
+			},
+		},
+		"Recovered": {
+			args: args{
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateHalfOpen},
+				},
+			},
+			want: want{
+				updated: true,
+			},
+		},
+		"Unchanged": {
+			args: args{
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+				tripped: []httpClient.TrippedHost{
+					{Host: "api.example.com", Since: testTripped, Until: testNow.Add(20 * time.Second)},
+				},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: 20 * time.Second},
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateOpen},
+				},
+			},
+		},
+		"UpdateFailed": {
+			args: args{
+				tripped: []httpClient.TrippedHost{
+					{Host: "api.example.com", Since: testTripped, HalfOpen: true},
+				},
+			},
+			want: want{
+				updated: true,
+				status: []v1alpha1.TrippedHost{
+					{Host: "api.example.com", Since: metav1.NewTime(testTripped), State: v1alpha1.CircuitStateHalfOpen},
+				},
+				err: errors.Wrap(errBoom, errUpdateTrippedHosts),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updateErr error
+			if tc.want.err != nil {
+				updateErr = errBoom
+			}
+
+			updated := false
+			var status []v1alpha1.TrippedHost
+			r := &circuitStatusReconciler{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+						obj.(*v1alpha1.ProviderConfig).Status.TrippedHosts = tc.args.status
+						return nil
+					}),
+					MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+						updated = true
+						status = obj.(*v1alpha1.ProviderConfig).Status.TrippedHosts
+						return updateErr
+					},
+				},
+				logger:  logging.NewNopLogger(),
+				tripped: func(string) []httpClient.TrippedHost { return tc.args.tripped },
+				now:     func() time.Time { return testNow },
+			}
+
+			result, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "http-conf"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Reconcile(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.result, result); diff != "" {
+				t.Fatalf("Reconcile(...): -want result, +got result: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.updated, updated); diff != "" {
+				t.Fatalf("Reconcile(...): -want updated, +got updated: %s", diff)
+			}
+
+			if !updated {
+				status = tc.args.status
+			}
+			if diff := cmp.Diff(tc.want.status, status); diff != "" {
+				t.Fatalf("Reconcile(...): -want tripped hosts, +got tripped hosts: %s", diff)
+			}
+		})
+	}
+}
@@ -45,6 +45,14 @@ func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error
 		providerconfig.WithLogger(o.Logger.WithValues("controller", name)),
 		providerconfig.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
 
+	if err := setupCircuitStatus(mgr, o); err != nil {
+		return err
+	}
+
+	if err := setupTransportEviction(mgr, o); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
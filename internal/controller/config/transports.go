@@ -0,0 +1,87 @@
+/*
+Copyright 2024 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/controller"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+
+	"github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+// setupTransportEviction adds a controller that closes the connections and
+// forgets the circuit breakers, rate limiters and tokens kept for the
+// ProviderConfigs that are deleted.
+func setupTransportEviction(mgr ctrl.Manager, o controller.Options) error {
+	name := "transports/" + strings.ToLower(v1alpha1.ProviderConfigGroupKind)
+
+	r := &transportEvictionReconciler{
+		kube:   mgr.GetClient(),
+		logger: o.Logger.WithValues("controller", name),
+		evict:  httpClient.EvictProviderConfig,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o.ForControllerRuntime()).
+		For(&v1alpha1.ProviderConfig{}, builder.WithPredicates(deleted())).
+		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+}
+
+// deleted only accepts the events of deleted objects.
+func deleted() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		UpdateFunc:  func(event.UpdateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return true },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// transportEvictionReconciler evicts the transports and registries of the
+// ProviderConfigs that no longer exist.
+type transportEvictionReconciler struct {
+	kube   client.Client
+	logger logging.Logger
+	evict  func(providerConfig string)
+}
+
+func (r *transportEvictionReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	err := r.kube.Get(ctx, req.NamespacedName, &v1alpha1.ProviderConfig{})
+	if !kerrors.IsNotFound(err) {
+		// The ProviderConfig exists again or cannot be read.
+		return reconcile.Result{}, errors.Wrap(err, errGetProviderConfig)
+	}
+
+	r.logger.Debug("Evicting transports and registries", "providerConfig", req.Name)
+	r.evict(req.Name)
+	return reconcile.Result{}, nil
+}
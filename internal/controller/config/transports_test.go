@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var errBoom = errors.New("boom")
+
+func Test_transportEvictionReconciler(t *testing.T) {
+	type args struct {
+		getErr error
+	}
+	type want struct {
+		evicted []string
+		err     error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Deleted": {
+			args: args{
+				getErr: kerrors.NewNotFound(schema.GroupResource{}, "http-conf"),
+			},
+			want: want{
+				evicted: []string{"http-conf"},
+			},
+		},
+		"CreatedAgain": {
+			args: args{},
+			want: want{},
+		},
+		"GetFailed": {
+			args: args{
+				getErr: errBoom,
+			},
+			want: want{
+				err: errors.Wrap(errBoom, errGetProviderConfig),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var evicted []string
+			r := &transportEvictionReconciler{
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(tc.args.getErr)},
+				logger: logging.NewNopLogger(),
+				evict:  func(providerConfig string) { evicted = append(evicted, providerConfig) },
+			}
+
+			_, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "http-conf"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Reconcile(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.evicted, evicted); diff != "" {
+				t.Fatalf("Reconcile(...): -want evicted, +got evicted: %s", diff)
+			}
+		})
+	}
+}
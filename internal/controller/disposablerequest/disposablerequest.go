@@ -24,9 +24,12 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	"github.com/crossplane-contrib/provider-http/internal/jq"
 	json_util "github.com/crossplane-contrib/provider-http/internal/json"
@@ -40,6 +43,9 @@ import (
 	"github.com/crossplane-contrib/provider-http/apis/disposablerequest/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/clients/providerconfig"
+	"github.com/crossplane-contrib/provider-http/internal/features"
+	"github.com/crossplane-contrib/provider-http/internal/secrets"
 	"github.com/crossplane-contrib/provider-http/internal/utils"
 )
 
@@ -47,44 +53,64 @@ const (
 	errNotDisposableRequest              = "managed resource is not a DisposableRequest custom resource"
 	errTrackPCUsage                      = "cannot track ProviderConfig usage"
 	errNewHttpClient                     = "cannot create new Http client"
+	errProviderConfigOptions             = "cannot resolve ProviderConfig settings"
 	errProviderNotRetrieved              = "provider could not be retrieved"
 	errFailedToSendHttpDisposableRequest = "failed to send http request"
 	errFailedUpdateStatusConditions      = "failed updating status conditions"
+	errRequestSigner                     = "cannot resolve request HMAC signer"
+	errResolveSecrets                    = "cannot resolve secret references"
+	errStatusMasking                     = "cannot build status masking rules"
+	errIndexSecretRefs                   = "cannot index referenced secrets"
 	ErrExpectedFormat                    = "JQ filter should return a boolean, but returned error: %s"
 )
 
-// Setup adds a controller that reconciles DisposableRequest managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
+// Setup adds a controller that reconciles DisposableRequest managed resources,
+// whose placeholders may reference the Secrets of the given scope.
+func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration, scope secrets.Scope) error {
 	name := managed.ControllerName(v1alpha1.DisposableRequestGroupKind)
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
+	requeues := utils.NewRequeues()
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.DisposableRequestGroupVersionKind),
+	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
 			logger:          o.Logger,
 			kube:            mgr.GetClient(),
 			usage:           resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newHttpClientFn: httpClient.NewClient,
+			secrets:         scope.Resolver(),
+			requeues:        requeues,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithTimeout(timeout),
 		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithConnectionPublishers(cps...),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.DisposableRequestGroupVersionKind), opts...)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.DisposableRequest{}, secrets.IndexKey, secrets.IndexReferencedSecrets); err != nil {
+		return errors.Wrap(err, errIndexSecretRefs)
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.DisposableRequest{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		For(&v1alpha1.DisposableRequest{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(source.NewKindWithCache(&corev1.Secret{}, scope.Cache), secrets.EnqueueReferencing(mgr.GetClient(), &v1alpha1.DisposableRequestList{})).
+		Complete(ratelimiter.NewReconciler(name, requeues.Reconciler(r), o.GlobalRateLimiter))
 }
 
 type connector struct {
 	logger          logging.Logger
 	kube            client.Client
 	usage           resource.Tracker
-	newHttpClientFn func(log logging.Logger, timeout time.Duration) (httpClient.Client, error)
+	newHttpClientFn func(log logging.Logger, timeout time.Duration, opts ...httpClient.Option) (httpClient.Client, error)
+	secrets         secrets.Resolver
+	requeues        *utils.Requeues
 }
 
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
@@ -105,7 +131,17 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errProviderNotRetrieved)
 	}
 
-	h, err := c.newHttpClientFn(l, utils.WaitTimeout(cr.Spec.ForProvider.WaitTimeout))
+	opts, err := providerconfig.ClientOptions(ctx, c.kube, pc, cr.Spec.ForProvider.TLSConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errProviderConfigOptions)
+	}
+
+	masker, err := providerconfig.StatusMasker(pc.Spec.StatusMasking, cr.Spec.ForProvider.StatusMasking)
+	if err != nil {
+		return nil, errors.Wrap(err, errStatusMasking)
+	}
+
+	h, err := c.newHttpClientFn(l, utils.WaitTimeout(cr.Spec.ForProvider.WaitTimeout), opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewHttpClient)
 	}
@@ -114,6 +150,9 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		localKube: c.kube,
 		logger:    l,
 		http:      h,
+		masker:    masker,
+		secrets:   c.secrets,
+		requeues:  c.requeues,
 	}, nil
 }
 
@@ -121,6 +160,20 @@ type external struct {
 	localKube client.Client
 	logger    logging.Logger
 	http      httpClient.Client
+	masker    *httpClient.Redactor
+	secrets   secrets.Resolver
+	requeues  *utils.Requeues
+}
+
+// rateLimited requeues cr once the rate limits that held back its request
+// allow it, telling whether err was caused by them. Such requests were not
+// sent, so they are not reported as errors.
+func (c *external) rateLimited(cr *v1alpha1.DisposableRequest, err error) bool {
+	after, ok := httpClient.RetryAfter(err)
+	if ok {
+		c.requeues.RequeueAfter(cr, after)
+	}
+	return ok
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -152,9 +205,35 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}, nil
 }
 
-func (c *external) deployAction(ctx context.Context, cr *v1alpha1.DisposableRequest) error {
+// deployAction sends the request and returns the connection details extracted
+// from its response once it is as expected.
+func (c *external) deployAction(ctx context.Context, cr *v1alpha1.DisposableRequest) (managed.ConnectionDetails, error) {
+	ctx = httpClient.ContextWithRetryOverride(ctx, providerconfig.RetryOverride(cr.Spec.ForProvider.Retry))
+
+	signer, err := providerconfig.Signer(ctx, c.localKube, cr.Spec.ForProvider.HMACSigner)
+	if err != nil {
+		return nil, errors.Wrap(err, errRequestSigner)
+	}
+	ctx = httpClient.ContextWithSigner(ctx, signer)
+
+	values, err := c.secrets.Resolve(ctx, secrets.RequestStrings(cr.Spec.ForProvider.URL, cr.Spec.ForProvider.Body, cr.Spec.ForProvider.Headers)...)
+	if err != nil {
+		return nil, errors.Wrap(err, errResolveSecrets)
+	}
+	if values != nil {
+		ctx = httpClient.ContextWithInterpolator(ctx, values)
+	}
+
 	details, err := c.http.SendRequest(ctx, cr.Spec.ForProvider.Method,
 		cr.Spec.ForProvider.URL, cr.Spec.ForProvider.Body, cr.Spec.ForProvider.Headers, cr.Spec.ForProvider.InsecureSkipTLSVerify)
+	if httpClient.IsRateLimited(err) {
+		return nil, err
+	}
+
+	if httpClient.IsCircuitOpen(err) {
+		cr.Status.SetConditions(apisv1alpha1.CircuitOpen(err))
+		return nil, err
+	}
 
 	res := details.HttpResponse
 	resource := &utils.RequestResource{
@@ -163,41 +242,51 @@ func (c *external) deployAction(ctx context.Context, cr *v1alpha1.DisposableRequ
 		HttpResponse:   details.HttpResponse,
 		LocalClient:    c.localKube,
 		HttpRequest:    details.HttpRequest,
+		Masker:         c.masker,
 	}
 
 	// Get the latest version of the resource before updating
 	if err := c.localKube.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, cr); err != nil {
-		return errors.Wrap(err, "failed to get the latest version of the resource")
+		return nil, errors.Wrap(err, "failed to get the latest version of the resource")
 	}
 
+	utils.SetCircuitClosed(cr)
+
 	if err != nil {
 		setErr := resource.SetError(err)
 		if settingError := utils.SetRequestResourceStatus(*resource, setErr, resource.SetRequestDetails()); settingError != nil {
-			return errors.Wrap(settingError, utils.ErrFailedToSetStatus)
+			return nil, errors.Wrap(settingError, utils.ErrFailedToSetStatus)
 		}
-		return err
+		return nil, err
 	}
 
 	if utils.IsHTTPError(res.StatusCode) {
 		if settingError := utils.SetRequestResourceStatus(*resource, resource.SetStatusCode(), resource.SetHeaders(), resource.SetBody(), resource.SetRequestDetails(), resource.SetError(nil)); settingError != nil {
-			return errors.Wrap(settingError, utils.ErrFailedToSetStatus)
+			return nil, errors.Wrap(settingError, utils.ErrFailedToSetStatus)
 		}
 
-		return errors.Errorf(utils.ErrStatusCode, cr.Spec.ForProvider.Method, strconv.Itoa(res.StatusCode))
+		return nil, errors.Errorf(utils.ErrStatusCode, cr.Spec.ForProvider.Method, strconv.Itoa(res.StatusCode))
 	}
 
 	isExpectedResponse, err := c.isResponseAsExpected(cr, res)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if !isExpectedResponse {
 		limit := utils.GetRollbackRetriesLimit(cr.Spec.ForProvider.RollbackRetriesLimit)
-		return utils.SetRequestResourceStatus(*resource, resource.SetStatusCode(), resource.SetHeaders(), resource.SetBody(),
+		return nil, utils.SetRequestResourceStatus(*resource, resource.SetStatusCode(), resource.SetHeaders(), resource.SetBody(),
 			resource.SetError(errors.New("Response does not match the expected format, retries limit "+fmt.Sprint(limit))), resource.SetRequestDetails())
 	}
 
-	return utils.SetRequestResourceStatus(*resource, resource.SetStatusCode(), resource.SetHeaders(), resource.SetBody(), resource.SetSynced(), resource.SetRequestDetails())
+	// The request is not sent again once synced, so the connection details
+	// are extracted first.
+	connectionDetails, err := utils.ConnectionDetails(cr.Spec.ForProvider.ConnectionDetails, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return connectionDetails, utils.SetRequestResourceStatus(*resource, resource.SetStatusCode(), resource.SetHeaders(), resource.SetBody(), resource.SetSynced(), resource.SetRequestDetails())
 }
 
 func (c *external) isResponseAsExpected(cr *v1alpha1.DisposableRequest, res httpClient.HttpResponse) (bool, error) {
@@ -235,7 +324,11 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, err
 	}
 
-	return managed.ExternalCreation{}, errors.Wrap(c.deployAction(ctx, cr), errFailedToSendHttpDisposableRequest)
+	connectionDetails, err := c.deployAction(ctx, cr)
+	if c.rateLimited(cr, err) {
+		return managed.ExternalCreation{}, nil
+	}
+	return managed.ExternalCreation{ConnectionDetails: connectionDetails}, errors.Wrap(err, errFailedToSendHttpDisposableRequest)
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -248,7 +341,11 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, err
 	}
 
-	return managed.ExternalUpdate{}, errors.Wrap(c.deployAction(ctx, cr), errFailedToSendHttpDisposableRequest)
+	connectionDetails, err := c.deployAction(ctx, cr)
+	if c.rateLimited(cr, err) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return managed.ExternalUpdate{ConnectionDetails: connectionDetails}, errors.Wrap(err, errFailedToSendHttpDisposableRequest)
 }
 
 func (c *external) Delete(_ context.Context, _ resource.Managed) error {
@@ -402,7 +402,7 @@ func Test_deployAction(t *testing.T) {
 				http:      tc.args.http,
 			}
 
-			gotErr := e.deployAction(context.Background(), tc.args.cr)
+			_, gotErr := e.deployAction(context.Background(), tc.args.cr)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("deployAction(...): -want error, +got error: %s", diff)
 			}
@@ -25,17 +25,27 @@ import (
 	"github.com/crossplane-contrib/provider-http/internal/controller/config"
 	disposablerequest "github.com/crossplane-contrib/provider-http/internal/controller/disposablerequest"
 	request "github.com/crossplane-contrib/provider-http/internal/controller/request"
+	"github.com/crossplane-contrib/provider-http/internal/secrets"
 )
 
 // Setup creates all http controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
-	for _, setup := range []func(ctrl.Manager, controller.Options, time.Duration) error{
-		config.Setup,
+// the supplied manager. Requests may only reference the Secrets of
+// secretNamespace.
+func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration, secretNamespace string) error {
+	if err := config.Setup(mgr, o, timeout); err != nil {
+		return err
+	}
+
+	scope, err := secrets.NewScope(mgr, secretNamespace)
+	if err != nil {
+		return err
+	}
+
+	for _, setup := range []func(ctrl.Manager, controller.Options, time.Duration, secrets.Scope) error{
 		disposablerequest.Setup,
 		request.Setup,
 	} {
-		if err := setup(mgr, o, timeout); err != nil {
+		if err := setup(mgr, o, timeout, scope); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,140 @@
+package request
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/statushandler"
+	"github.com/crossplane-contrib/provider-http/internal/jq"
+	"github.com/crossplane-contrib/provider-http/internal/utils"
+)
+
+const (
+	errAdopt            = "cannot adopt an existing resource"
+	errAdoptionSelector = "cannot evaluate the selector of the adoption"
+)
+
+// adoption returns the adoption settings of cr, if any. Requests with an
+// external name identify an existing resource, and Requests observing their
+// resource only never send the POST mapping, so both adopt it with the GET
+// mapping by default.
+func adoption(cr *v1alpha1.Request) *v1alpha1.Adoption {
+	if cr.Spec.ForProvider.Adoption == nil && (meta.GetExternalName(cr) != "" || isObserveOnly(cr)) {
+		return &v1alpha1.Adoption{}
+	}
+	return cr.Spec.ForProvider.Adoption
+}
+
+// shouldAdopt tells whether an existing resource is looked up for cr before
+// the POST mapping is sent.
+func (c *external) shouldAdopt(cr *v1alpha1.Request) bool {
+	return adoption(cr) != nil && !meta.WasDeleted(cr) && !c.isObjectValidForObservation(cr)
+}
+
+// shouldAdoptOnConflict tells whether the POST mapping of cr was just answered
+// with 409 Conflict and the resource is then looked up.
+func shouldAdoptOnConflict(cr *v1alpha1.Request) bool {
+	adoption := cr.Spec.ForProvider.Adoption
+	return adoption != nil && adoption.OnConflict &&
+		cr.Status.RequestDetails.Method == http.MethodPost && cr.Status.Response.StatusCode == http.StatusConflict
+}
+
+// adopt looks up an existing resource for cr with its adoption settings and
+// tells whether one was found. The response of the lookup is then stored in
+// the status of cr, so the resource is observed and updated like one the POST
+// mapping created.
+func (c *external) adopt(ctx context.Context, cr *v1alpha1.Request) (bool, error) {
+	adoption := adoption(cr)
+
+	mapping := adoption.Lookup
+	if mapping == nil {
+		getMapping, ok := getMappingByMethod(&cr.Spec.ForProvider, http.MethodGet)
+		if !ok {
+			return false, errors.Errorf(errMappingNotFound, http.MethodGet)
+		}
+		mapping = getMapping
+	}
+
+	requestDetails, err := c.generateValidRequestDetails(ctx, cr, mapping)
+	if err != nil {
+		return false, err
+	}
+	if !requestgen.IsRequestValid(requestDetails) {
+		// The lookup needs values the Request doesn't have yet.
+		return false, nil
+	}
+
+	ctx, err = c.mappingContext(ctx, mapping, requestDetails)
+	if err != nil {
+		return false, err
+	}
+
+	details, err := c.http.SendRequest(ctx, mapping.Method, requestDetails.Url, requestDetails.Body, requestDetails.Headers, cr.Spec.ForProvider.InsecureSkipTLSVerify)
+	if httpClient.IsCircuitOpen(err) {
+		cr.Status.SetConditions(apisv1alpha1.CircuitOpen(err))
+	}
+	if err != nil {
+		return false, err
+	}
+
+	body, found, err := lookupResult(mapping, adoption.Selector, details.HttpResponse)
+	if err != nil || !found {
+		return false, err
+	}
+
+	details.HttpResponse.Body = body
+	statusHandler, err := statushandler.NewStatusHandler(ctx, cr, details, nil, c.localKube, c.logger, c.masking)
+	if err != nil {
+		return false, err
+	}
+
+	utils.SetCircuitClosed(cr)
+
+	statusHandler.ResetFailures()
+	if err := statusHandler.SetRequestStatus(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// lookupResult returns the body of the resource the lookup mapping found in
+// response, if any.
+func lookupResult(mapping *v1alpha1.Mapping, selector string, response httpClient.HttpResponse) (string, bool, error) {
+	if selector == "" {
+		if !utils.IsHTTPSuccess(response.StatusCode) {
+			return "", false, nil
+		}
+		exists, err := resourceExists(mapping, response)
+		if err != nil || !exists {
+			return "", false, err
+		}
+		return response.Body, true, nil
+	}
+
+	value, err := jq.ParseValue(selector, utils.ResponseObject(response))
+	if err != nil {
+		return "", false, errors.Wrap(err, errAdoptionSelector)
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return "", false, nil
+	case string:
+		return v, true, nil
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false, errors.Wrap(err, errAdoptionSelector)
+		}
+		return string(b), true, nil
+	}
+}
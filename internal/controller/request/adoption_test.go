@@ -0,0 +1,321 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+var testLookupMapping = v1alpha1.Mapping{
+	Method: "GET",
+	URL:    `(.payload.baseUrl + "?name=" + .externalName)`,
+}
+
+func adopting(adoption v1alpha1.Adoption) httpRequestModifier {
+	return func(r *v1alpha1.Request) {
+		meta.SetExternalName(r, "john_doe")
+		r.Spec.ForProvider.Adoption = &adoption
+	}
+}
+
+func Test_adoption(t *testing.T) {
+	lookup := &v1alpha1.Adoption{Lookup: &testLookupMapping}
+	cases := map[string]struct {
+		cr   *v1alpha1.Request
+		want *v1alpha1.Adoption
+	}{
+		"NoAdoption": {
+			cr:   httpRequest(),
+			want: nil,
+		},
+		"Adoption": {
+			cr: httpRequest(func(r *v1alpha1.Request) {
+				r.Spec.ForProvider.Adoption = lookup
+			}),
+			want: lookup,
+		},
+		"ExternalName": {
+			cr: httpRequest(func(r *v1alpha1.Request) {
+				meta.SetExternalName(r, "john_doe")
+			}),
+			want: &v1alpha1.Adoption{},
+		},
+		"ObserveOnly": {
+			cr: httpRequest(func(r *v1alpha1.Request) {
+				r.Spec.ManagementPolicy = xpv1.ManagementObserveOnly
+			}),
+			want: &v1alpha1.Adoption{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, adoption(tc.cr)); diff != "" {
+				t.Fatalf("adoption(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_lookupResult(t *testing.T) {
+	type args struct {
+		mapping  v1alpha1.Mapping
+		selector string
+		response httpClient.HttpResponse
+	}
+	type want struct {
+		body  string
+		found bool
+		err   error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Found": {
+			args: args{
+				mapping:  testGetMapping,
+				response: httpClient.HttpResponse{StatusCode: 200, Body: `{"id":"123"}`},
+			},
+			want: want{body: `{"id":"123"}`, found: true},
+		},
+		"NotFound": {
+			args: args{
+				mapping:  testGetMapping,
+				response: httpClient.HttpResponse{StatusCode: 404},
+			},
+			want: want{},
+		},
+		"ExistenceCheck": {
+			args: args{
+				mapping:  v1alpha1.Mapping{Method: "GET", ExistsWhen: "(.body.items | length) > 0"},
+				response: httpClient.HttpResponse{StatusCode: 200, Body: `{"items":[]}`},
+			},
+			want: want{},
+		},
+		"Selector": {
+			args: args{
+				mapping:  testLookupMapping,
+				selector: `first(.body.items[] | select(.username == "john_doe")) // null`,
+				response: httpClient.HttpResponse{StatusCode: 200, Body: `{"items":[{"id":"122","username":"jane_doe"},{"id":"123","username":"john_doe"}]}`},
+			},
+			want: want{body: `{"id":"123","username":"john_doe"}`, found: true},
+		},
+		"SelectorNotFound": {
+			args: args{
+				mapping:  testLookupMapping,
+				selector: `first(.body.items[] | select(.username == "john_doe")) // null`,
+				response: httpClient.HttpResponse{StatusCode: 200, Body: `{"items":[{"id":"122","username":"jane_doe"}]}`},
+			},
+			want: want{},
+		},
+		"InvalidSelector": {
+			args: args{
+				mapping:  testLookupMapping,
+				selector: `.body.items[0].id`,
+				response: httpClient.HttpResponse{StatusCode: 200, Body: `{"items":{}}`},
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf("failed to parse given mapping - %s jq error: %s", ".body.items[0].id", "expected an array but got: object ({})"), errAdoptionSelector),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			body, found, err := lookupResult(&tc.args.mapping, tc.args.selector, tc.args.response)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("lookupResult(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.found, found); diff != "" {
+				t.Fatalf("lookupResult(...): -want found, +got found: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.body, body); diff != "" {
+				t.Fatalf("lookupResult(...): -want body, +got body: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_httpExternal_adopt(t *testing.T) {
+	type args struct {
+		http httpClient.Client
+		mg   *v1alpha1.Request
+	}
+	type want struct {
+		adopted bool
+		err     error
+		body    string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Lookup": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						if url != "https://api.example.com/users?name=john_doe" {
+							return httpClient.HttpDetails{}, errors.Errorf("unexpected request to %s", url)
+						}
+						return httpClient.HttpDetails{
+							HttpRequest:  httpClient.HttpRequest{Method: method, URL: url},
+							HttpResponse: httpClient.HttpResponse{StatusCode: 200, Body: `{"items":[{"id":"123","username":"john_doe"}]}`},
+						}, nil
+					},
+				},
+				mg: httpRequest(adopting(v1alpha1.Adoption{Lookup: &testLookupMapping, Selector: ".body.items[0] // null"})),
+			},
+			want: want{adopted: true, body: `{"id":"123","username":"john_doe"}`},
+		},
+		"NotFound": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{HttpResponse: httpClient.HttpResponse{StatusCode: 404}}, nil
+					},
+				},
+				mg: httpRequest(adopting(v1alpha1.Adoption{Lookup: &testLookupMapping})),
+			},
+			want: want{},
+		},
+		"LookupNotRenderable": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{}, errBoom
+					},
+				},
+				mg: httpRequest(adopting(v1alpha1.Adoption{Lookup: &v1alpha1.Mapping{
+					Method: "GET",
+					URL:    `(.payload.baseUrl + "/" + (.response.body.id | tostring))`,
+				}})),
+			},
+			want: want{},
+		},
+		"ObserveOnly": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						if method != http.MethodGet {
+							return httpClient.HttpDetails{}, errors.Errorf("unexpected %s request", method)
+						}
+						return httpClient.HttpDetails{
+							HttpRequest:  httpClient.HttpRequest{Method: method, URL: url},
+							HttpResponse: httpClient.HttpResponse{StatusCode: 200, Body: `{"id":"123"}`},
+						}, nil
+					},
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ManagementPolicy = xpv1.ManagementObserveOnly
+				}),
+			},
+			want: want{adopted: true, body: `{"id":"123"}`},
+		},
+		"RequestFailed": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{}, errBoom
+					},
+				},
+				mg: httpRequest(adopting(v1alpha1.Adoption{Lookup: &testLookupMapping})),
+			},
+			want: want{err: errBoom},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				localKube: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				logger: logging.NewNopLogger(),
+				http:   tc.args.http,
+			}
+			adopted, err := e.adopt(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.adopt(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.adopted, adopted); diff != "" {
+				t.Fatalf("e.adopt(...): -want adopted, +got adopted: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.body, tc.args.mg.Status.Response.Body); diff != "" {
+				t.Fatalf("e.adopt(...): -want response body, +got response body: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_httpExternal_CreateConflict(t *testing.T) {
+	type args struct {
+		adoption v1alpha1.Adoption
+	}
+	type want struct {
+		err  error
+		body string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Adopted": {
+			args: args{
+				adoption: v1alpha1.Adoption{Lookup: &testLookupMapping, OnConflict: true},
+			},
+			want: want{body: `{"id":"123","username":"john_doe"}`},
+		},
+		"NotAdoptedOnConflict": {
+			args: args{
+				adoption: v1alpha1.Adoption{Lookup: &testLookupMapping},
+			},
+			want: want{
+				err:  errors.Wrap(errors.Errorf("HTTP %s request failed with status code: %s", http.MethodPost, "409"), errFailedToSendHttpRequest),
+				body: `{"error":"already exists"}`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := httpRequest(adopting(tc.args.adoption))
+			e := &external{
+				localKube: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				logger: logging.NewNopLogger(),
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						request := httpClient.HttpRequest{Method: method, URL: url}
+						if method == http.MethodPost {
+							return httpClient.HttpDetails{HttpRequest: request, HttpResponse: httpClient.HttpResponse{StatusCode: http.StatusConflict, Body: `{"error":"already exists"}`}}, nil
+						}
+						return httpClient.HttpDetails{HttpRequest: request, HttpResponse: httpClient.HttpResponse{StatusCode: 200, Body: `{"id":"123","username":"john_doe"}`}}, nil
+					},
+				},
+			}
+
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Create(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.body, cr.Status.Response.Body); diff != "" {
+				t.Fatalf("e.Create(...): -want response body, +got response body: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,223 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
+	"github.com/crossplane-contrib/provider-http/internal/jq"
+	"github.com/crossplane-contrib/provider-http/internal/utils"
+)
+
+const (
+	errOperationURL      = "cannot get the URL of the asynchronous operation of the %s mapping"
+	errOperationCheck    = "cannot evaluate the %s expression of the asynchronous operation"
+	errOperationFailed   = "asynchronous operation of the %s mapping failed with status code %d"
+	errOperationTimedOut = "asynchronous operation of the %s mapping didn't finish within %s"
+	errPollOperation     = "cannot poll the asynchronous operation"
+	errSaveOperation     = "cannot save the asynchronous operation"
+	completedWhenField   = "completedWhen"
+	failedWhenField      = "failedWhen"
+
+	defaultOperationPollInterval = 10 * time.Second
+)
+
+// startAsyncOperation records the asynchronous operation the API started when
+// it answered the request of mapping with 202 Accepted, if mapping follows
+// them. The Request waits for the operation until it finishes.
+func (c *external) startAsyncOperation(ctx context.Context, cr *v1alpha1.Request, mapping *v1alpha1.Mapping, details httpClient.HttpDetails) error {
+	if mapping.AsyncOperation == nil || details.HttpResponse.StatusCode != http.StatusAccepted {
+		return nil
+	}
+
+	operationURL, err := operationURL(mapping, details)
+	if err != nil {
+		return err
+	}
+
+	cr.Status.AsyncOperation = &v1alpha1.AsyncOperationStatus{
+		Method:    mapping.Method,
+		URL:       operationURL,
+		StartTime: metav1.Now(),
+	}
+	if condition, ok := operationCondition(mapping.Method); ok {
+		cr.Status.SetConditions(condition)
+	}
+
+	return errors.Wrap(c.localKube.Status().Update(ctx, cr), errSaveOperation)
+}
+
+// operationURL returns the URL of the operation started by the request of
+// mapping, read from the Location header unless the mapping sets an
+// expression.
+func operationURL(mapping *v1alpha1.Mapping, details httpClient.HttpDetails) (string, error) {
+	location := http.Header(details.HttpResponse.Headers).Get("Location")
+	if expression := mapping.AsyncOperation.URL; expression != "" {
+		var err error
+		if location, err = jq.ParseString(expression, utils.ResponseObject(details.HttpResponse)); err != nil {
+			return "", errors.Wrapf(err, errOperationURL, mapping.Method)
+		}
+	}
+
+	if location == "" {
+		return "", errors.Errorf(errOperationURL, mapping.Method)
+	}
+
+	base, err := url.Parse(details.HttpRequest.URL)
+	if err != nil {
+		return "", errors.Wrapf(err, errOperationURL, mapping.Method)
+	}
+	ref, err := url.Parse(location)
+	if err != nil {
+		return "", errors.Wrapf(err, errOperationURL, mapping.Method)
+	}
+
+	return base.ResolveReference(ref).String(), nil
+}
+
+// observeAsyncOperation polls the asynchronous operation cr waits for and
+// tells whether it is still pending. Finished operations are removed from the
+// status, and failed ones are returned as errors.
+func (c *external) observeAsyncOperation(ctx context.Context, cr *v1alpha1.Request) (bool, error) {
+	operation := cr.Status.AsyncOperation
+
+	mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, operation.Method)
+	if !ok || mapping.AsyncOperation == nil {
+		// The mapping no longer follows its operations.
+		return false, c.finishAsyncOperation(ctx, cr)
+	}
+
+	if timeout := mapping.AsyncOperation.Timeout; timeout != nil && time.Since(operation.StartTime.Time) > timeout.Duration {
+		if err := c.finishAsyncOperation(ctx, cr); err != nil {
+			return false, err
+		}
+		return false, errors.Errorf(errOperationTimedOut, operation.Method, timeout.Duration)
+	}
+
+	details, err := c.pollAsyncOperation(ctx, cr, mapping)
+	if err != nil {
+		return true, errors.Wrap(err, errPollOperation)
+	}
+
+	completed, failed, err := operationState(mapping.AsyncOperation, details.HttpResponse)
+	if err != nil {
+		return true, err
+	}
+
+	if failed {
+		if err := c.finishAsyncOperation(ctx, cr); err != nil {
+			return false, err
+		}
+		return false, errors.Errorf(errOperationFailed, operation.Method, details.HttpResponse.StatusCode)
+	}
+
+	if !completed {
+		if condition, ok := operationCondition(operation.Method); ok {
+			cr.Status.SetConditions(condition)
+		}
+		return true, nil
+	}
+
+	return false, c.finishAsyncOperation(ctx, cr)
+}
+
+// pollAsyncOperation requests the URL of the operation cr waits for, with the
+// headers, retry policy and signer of mapping.
+func (c *external) pollAsyncOperation(ctx context.Context, cr *v1alpha1.Request, mapping *v1alpha1.Mapping) (httpClient.HttpDetails, error) {
+	requestDetails, err := c.generateValidRequestDetails(ctx, cr, mapping)
+	if err != nil {
+		return httpClient.HttpDetails{}, err
+	}
+
+	requestDetails = requestgen.RequestDetails{Url: cr.Status.AsyncOperation.URL, Headers: requestDetails.Headers}
+	ctx, err = c.mappingContext(ctx, mapping, requestDetails)
+	if err != nil {
+		return httpClient.HttpDetails{}, err
+	}
+
+	return c.http.SendRequest(ctx, http.MethodGet, requestDetails.Url, "", requestDetails.Headers, cr.Spec.ForProvider.InsecureSkipTLSVerify)
+}
+
+// operationState tells whether response shows the operation completed or
+// failed, following the expressions of operation when set.
+func operationState(operation *v1alpha1.AsyncOperation, response httpClient.HttpResponse) (completed, failed bool, err error) {
+	obj := utils.ResponseObject(response)
+
+	if operation.FailedWhen != "" {
+		if failed, err = jq.ParseBool(operation.FailedWhen, obj); err != nil {
+			return false, false, errors.Wrapf(err, errOperationCheck, failedWhenField)
+		}
+	} else {
+		failed = utils.IsHTTPError(response.StatusCode)
+	}
+	if failed {
+		return false, true, nil
+	}
+
+	if operation.CompletedWhen != "" {
+		if completed, err = jq.ParseBool(operation.CompletedWhen, obj); err != nil {
+			return false, false, errors.Wrapf(err, errOperationCheck, completedWhenField)
+		}
+	} else {
+		completed = utils.IsHTTPSuccess(response.StatusCode) && response.StatusCode != http.StatusAccepted
+	}
+
+	return completed, false, nil
+}
+
+// finishAsyncOperation removes the operation cr waits for from its status.
+func (c *external) finishAsyncOperation(ctx context.Context, cr *v1alpha1.Request) error {
+	cr.Status.AsyncOperation = nil
+	return errors.Wrap(c.localKube.Status().Update(ctx, cr), errSaveOperation)
+}
+
+// operationCondition returns the condition of a Request waiting for an
+// operation started by the mapping of method, if any.
+func operationCondition(method string) (xpv1.Condition, bool) {
+	switch method {
+	case http.MethodPost:
+		return xpv1.Creating(), true
+	case http.MethodDelete:
+		return xpv1.Deleting(), true
+	}
+	return xpv1.Condition{}, false
+}
+
+// operationPoller requeues the Requests waiting for an asynchronous operation
+// after the poll interval of the operation rather than that of the provider.
+type operationPoller struct {
+	reconcile.Reconciler
+	kube client.Client
+}
+
+func (p *operationPoller) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	result, err := p.Reconciler.Reconcile(ctx, req)
+	if err != nil || (result.Requeue && result.RequeueAfter == 0) {
+		return result, err
+	}
+
+	cr := &v1alpha1.Request{}
+	if getErr := p.kube.Get(ctx, req.NamespacedName, cr); getErr != nil || cr.Status.AsyncOperation == nil {
+		return result, err
+	}
+
+	interval := defaultOperationPollInterval
+	if mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, cr.Status.AsyncOperation.Method); ok && mapping.AsyncOperation != nil && mapping.AsyncOperation.PollInterval != nil {
+		interval = mapping.AsyncOperation.PollInterval.Duration
+	}
+
+	if result.RequeueAfter == 0 || interval < result.RequeueAfter {
+		result.RequeueAfter = interval
+	}
+	return result, err
+}
@@ -0,0 +1,400 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const testOperationURL = "https://api.example.com/operations/42"
+
+// withAsyncOperation follows the asynchronous operations of the mapping of
+// method.
+func withAsyncOperation(method string, operation v1alpha1.AsyncOperation) httpRequestModifier {
+	return func(r *v1alpha1.Request) {
+		mappings := make([]v1alpha1.Mapping, len(r.Spec.ForProvider.Mappings))
+		copy(mappings, r.Spec.ForProvider.Mappings)
+		for i := range mappings {
+			if mappings[i].Method == method {
+				mappings[i].AsyncOperation = &operation
+			}
+		}
+		r.Spec.ForProvider.Mappings = mappings
+	}
+}
+
+// waitingFor makes the Request wait for an operation of the mapping of method
+// started at start.
+func waitingFor(method string, start time.Time) httpRequestModifier {
+	return func(r *v1alpha1.Request) {
+		r.Status.Response.Body = `{"id":"123"}`
+		r.Status.AsyncOperation = &v1alpha1.AsyncOperationStatus{
+			Method:    method,
+			URL:       testOperationURL,
+			StartTime: metav1.NewTime(start),
+		}
+	}
+}
+
+func respond(statusCode int, body string) *MockHttpClient {
+	return &MockHttpClient{
+		MockSendRequest: func(ctx context.Context, method string, url string, b string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+			if method != http.MethodGet || url != testOperationURL {
+				return httpClient.HttpDetails{}, errors.Errorf("unexpected %s request to %s", method, url)
+			}
+			return httpClient.HttpDetails{HttpResponse: httpClient.HttpResponse{StatusCode: statusCode, Body: body}}, nil
+		},
+	}
+}
+
+func Test_operationURL(t *testing.T) {
+	type args struct {
+		operation v1alpha1.AsyncOperation
+		details   httpClient.HttpDetails
+	}
+	type want struct {
+		url string
+		err error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"LocationHeader": {
+			args: args{
+				details: httpClient.HttpDetails{
+					HttpRequest:  httpClient.HttpRequest{URL: "https://api.example.com/users"},
+					HttpResponse: httpClient.HttpResponse{StatusCode: 202, Headers: map[string][]string{"Location": {testOperationURL}}},
+				},
+			},
+			want: want{url: testOperationURL},
+		},
+		"RelativeLocationHeader": {
+			args: args{
+				details: httpClient.HttpDetails{
+					HttpRequest:  httpClient.HttpRequest{URL: "https://api.example.com/users"},
+					HttpResponse: httpClient.HttpResponse{StatusCode: 202, Headers: map[string][]string{"Location": {"/operations/42"}}},
+				},
+			},
+			want: want{url: testOperationURL},
+		},
+		"Expression": {
+			args: args{
+				operation: v1alpha1.AsyncOperation{URL: ".body.operation.href"},
+				details: httpClient.HttpDetails{
+					HttpRequest:  httpClient.HttpRequest{URL: "https://api.example.com/users"},
+					HttpResponse: httpClient.HttpResponse{StatusCode: 202, Body: `{"operation":{"href":"operations/42"}}`},
+				},
+			},
+			want: want{url: testOperationURL},
+		},
+		"NoURL": {
+			args: args{
+				details: httpClient.HttpDetails{
+					HttpRequest:  httpClient.HttpRequest{URL: "https://api.example.com/users"},
+					HttpResponse: httpClient.HttpResponse{StatusCode: 202},
+				},
+			},
+			want: want{err: errors.Errorf(errOperationURL, http.MethodPost)},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mapping := &v1alpha1.Mapping{Method: http.MethodPost, AsyncOperation: &tc.args.operation}
+			got, err := operationURL(mapping, tc.args.details)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("operationURL(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.url, got); diff != "" {
+				t.Fatalf("operationURL(...): -want url, +got url: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_operationState(t *testing.T) {
+	type args struct {
+		operation v1alpha1.AsyncOperation
+		response  httpClient.HttpResponse
+	}
+	type want struct {
+		completed bool
+		failed    bool
+		err       error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Accepted": {
+			args: args{
+				response: httpClient.HttpResponse{StatusCode: 202},
+			},
+			want: want{},
+		},
+		"Completed": {
+			args: args{
+				response: httpClient.HttpResponse{StatusCode: 200},
+			},
+			want: want{completed: true},
+		},
+		"Failed": {
+			args: args{
+				response: httpClient.HttpResponse{StatusCode: 500},
+			},
+			want: want{failed: true},
+		},
+		"CompletedWhen": {
+			args: args{
+				operation: v1alpha1.AsyncOperation{CompletedWhen: `.body.status == "Succeeded"`},
+				response:  httpClient.HttpResponse{StatusCode: 200, Body: `{"status":"Running"}`},
+			},
+			want: want{},
+		},
+		"FailedWhen": {
+			args: args{
+				operation: v1alpha1.AsyncOperation{CompletedWhen: `.body.status == "Succeeded"`, FailedWhen: `.body.status == "Failed"`},
+				response:  httpClient.HttpResponse{StatusCode: 200, Body: `{"status":"Failed"}`},
+			},
+			want: want{failed: true},
+		},
+		"InvalidExpression": {
+			args: args{
+				operation: v1alpha1.AsyncOperation{CompletedWhen: `.body.status`},
+				response:  httpClient.HttpResponse{StatusCode: 200, Body: `{"status":"Running"}`},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("failed to parse string: %s", "Running"), errOperationCheck, completedWhenField),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			completed, failed, err := operationState(&tc.args.operation, tc.args.response)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("operationState(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.completed, completed); diff != "" {
+				t.Fatalf("operationState(...): -want completed, +got completed: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.failed, failed); diff != "" {
+				t.Fatalf("operationState(...): -want failed, +got failed: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_startAsyncOperation(t *testing.T) {
+	cr := httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{}))
+	e := &external{
+		localKube: &test.MockClient{
+			MockGet:          test.NewMockGetFn(nil),
+			MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+		},
+		logger: logging.NewNopLogger(),
+		http: &MockHttpClient{
+			MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+				return httpClient.HttpDetails{
+					HttpRequest:  httpClient.HttpRequest{Method: method, URL: url},
+					HttpResponse: httpClient.HttpResponse{StatusCode: http.StatusAccepted, Headers: map[string][]string{"Location": {"/operations/42"}}},
+				}, nil
+			},
+		},
+	}
+
+	if _, err := e.Create(context.Background(), cr); err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %s", err)
+	}
+
+	operation := cr.Status.AsyncOperation
+	if operation == nil {
+		t.Fatalf("e.Create(...): expected an asynchronous operation")
+	}
+	if diff := cmp.Diff(v1alpha1.AsyncOperationStatus{Method: http.MethodPost, URL: testOperationURL}, *operation, cmp.FilterPath(func(p cmp.Path) bool {
+		return p.String() == "StartTime"
+	}, cmp.Ignore())); diff != "" {
+		t.Fatalf("e.Create(...): -want operation, +got operation: %s", diff)
+	}
+	if diff := cmp.Diff(xpv1.ReasonCreating, cr.Status.GetCondition(xpv1.TypeReady).Reason); diff != "" {
+		t.Fatalf("e.Create(...): -want ready reason, +got ready reason: %s", diff)
+	}
+}
+
+func Test_httpExternal_ObserveAsyncOperation(t *testing.T) {
+	type args struct {
+		http httpClient.Client
+		mg   *v1alpha1.Request
+	}
+	type want struct {
+		pending     bool
+		err         error
+		readyReason xpv1.ConditionReason
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"CreationPending": {
+			args: args{
+				http: respond(http.StatusAccepted, ""),
+				mg:   httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{}), waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{pending: true, readyReason: xpv1.ReasonCreating},
+		},
+		"DeletionPending": {
+			args: args{
+				http: respond(http.StatusOK, `{"status":"Running"}`),
+				mg: httpRequest(withAsyncOperation(http.MethodDelete, v1alpha1.AsyncOperation{CompletedWhen: `.body.status == "Succeeded"`}),
+					waitingFor(http.MethodDelete, time.Now())),
+			},
+			want: want{pending: true, readyReason: xpv1.ReasonDeleting},
+		},
+		"Completed": {
+			args: args{
+				http: respond(http.StatusOK, `{"status":"Succeeded"}`),
+				mg: httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{CompletedWhen: `.body.status == "Succeeded"`}),
+					waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{},
+		},
+		"Failed": {
+			args: args{
+				http: respond(http.StatusInternalServerError, ""),
+				mg:   httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{}), waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{err: errors.Errorf(errOperationFailed, http.MethodPost, http.StatusInternalServerError)},
+		},
+		"TimedOut": {
+			args: args{
+				http: respond(http.StatusAccepted, ""),
+				mg: httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{Timeout: &metav1.Duration{Duration: time.Minute}}),
+					waitingFor(http.MethodPost, time.Now().Add(-2*time.Minute))),
+			},
+			want: want{err: errors.Errorf(errOperationTimedOut, http.MethodPost, time.Minute)},
+		},
+		"NoLongerFollowed": {
+			args: args{
+				http: respond(http.StatusAccepted, ""),
+				mg:   httpRequest(waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				logger: logging.NewNopLogger(),
+				http:   tc.args.http,
+			}
+			pending, err := e.observeAsyncOperation(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.observeAsyncOperation(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.pending, pending); diff != "" {
+				t.Fatalf("e.observeAsyncOperation(...): -want pending, +got pending: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.pending, tc.args.mg.Status.AsyncOperation != nil); diff != "" {
+				t.Fatalf("e.observeAsyncOperation(...): -want operation in status, +got operation in status: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.readyReason, tc.args.mg.Status.GetCondition(xpv1.TypeReady).Reason); diff != "" {
+				t.Fatalf("e.observeAsyncOperation(...): -want ready reason, +got ready reason: %s", diff)
+			}
+		})
+	}
+}
+
+type reconcileFn func(ctx context.Context, req reconcile.Request) (reconcile.Result, error)
+
+func (fn reconcileFn) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	return fn(ctx, req)
+}
+
+func Test_operationPoller(t *testing.T) {
+	type args struct {
+		result reconcile.Result
+		mg     *v1alpha1.Request
+	}
+	type want struct {
+		result reconcile.Result
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoOperation": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				mg:     httpRequest(),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"DefaultPollInterval": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				mg:     httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{}), waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: defaultOperationPollInterval}},
+		},
+		"PollInterval": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+				mg: httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{PollInterval: &metav1.Duration{Duration: 2 * time.Second}}),
+					waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{result: reconcile.Result{RequeueAfter: 2 * time.Second}},
+		},
+		"RequeuedSooner": {
+			args: args{
+				result: reconcile.Result{Requeue: true},
+				mg:     httpRequest(withAsyncOperation(http.MethodPost, v1alpha1.AsyncOperation{}), waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{result: reconcile.Result{Requeue: true}},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := &operationPoller{
+				Reconciler: reconcileFn(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+					return tc.args.result, nil
+				}),
+				kube: &test.MockClient{
+					MockGet: func(ctx context.Context, key client.ObjectKey, obj client.Object) error {
+						tc.args.mg.DeepCopyInto(obj.(*v1alpha1.Request))
+						return nil
+					},
+				},
+			}
+			got, err := p.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: testRequestName}})
+			if err != nil {
+				t.Fatalf("p.Reconcile(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Fatalf("p.Reconcile(...): -want result, +got result: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+)
+
+const (
+	errDeletionNotVerified = "resource still exists %s after the DELETE mapping was sent"
+
+	defaultDeletionVerificationTimeout = 10 * time.Minute
+)
+
+// verifyDeletion is called while the resource of cr, whose deletion was
+// requested, still exists. It reports resources still existing once the
+// timeout of verification elapsed.
+func verifyDeletion(cr *v1alpha1.Request, verification *v1alpha1.DeletionVerification) error {
+	timeout := defaultDeletionVerificationTimeout
+	if verification.Timeout != nil {
+		timeout = verification.Timeout.Duration
+	}
+
+	if time.Since(cr.Status.DeletionRequested.Time) < timeout {
+		return nil
+	}
+
+	err := errors.Errorf(errDeletionNotVerified, timeout)
+	cr.Status.SetConditions(v1alpha1.DeletionNotVerified(err))
+	return err
+}
@@ -0,0 +1,113 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+func Test_httpExternal_DeleteVerification(t *testing.T) {
+	verified := func(timeout *metav1.Duration) httpRequestModifier {
+		return func(r *v1alpha1.Request) {
+			r.Status.Response.Body = `{"id":"123"}`
+			r.Spec.ForProvider.DeletionVerification = &v1alpha1.DeletionVerification{Timeout: timeout}
+		}
+	}
+	requestedAt := func(requested time.Time) httpRequestModifier {
+		return func(r *v1alpha1.Request) {
+			requestTime := metav1.NewTime(requested)
+			r.Status.DeletionRequested = &requestTime
+		}
+	}
+
+	type args struct {
+		mg *v1alpha1.Request
+	}
+	type want struct {
+		sent      bool
+		requested bool
+		err       error
+		condition xpv1.ConditionReason
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NotVerified": {
+			args: args{
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+			},
+			want: want{sent: true},
+		},
+		"DeletionRequested": {
+			args: args{
+				mg: httpRequest(verified(nil)),
+			},
+			want: want{sent: true, requested: true},
+		},
+		"StillExists": {
+			args: args{
+				mg: httpRequest(verified(nil), requestedAt(time.Now().Add(-time.Minute))),
+			},
+			want: want{requested: true},
+		},
+		"StillExistsAfterTimeout": {
+			args: args{
+				mg: httpRequest(verified(&metav1.Duration{Duration: time.Minute}), requestedAt(time.Now().Add(-2*time.Minute))),
+			},
+			want: want{
+				requested: true,
+				err:       errors.Errorf(errDeletionNotVerified, time.Minute),
+				condition: v1alpha1.ReasonResourceStillExists,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sent := false
+			e := &external{
+				localKube: &test.MockClient{
+					MockGet:          test.NewMockGetFn(nil),
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				logger: logging.NewNopLogger(),
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						sent = method == http.MethodDelete
+						return httpClient.HttpDetails{HttpResponse: httpClient.HttpResponse{StatusCode: http.StatusNoContent}}, nil
+					},
+				},
+			}
+
+			err := e.Delete(context.Background(), tc.args.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("e.Delete(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.sent, sent); diff != "" {
+				t.Fatalf("e.Delete(...): -want DELETE sent, +got DELETE sent: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.requested, tc.args.mg.Status.DeletionRequested != nil); diff != "" {
+				t.Fatalf("e.Delete(...): -want deletion requested, +got deletion requested: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.condition, tc.args.mg.Status.GetCondition(v1alpha1.TypeDeletionVerified).Reason); diff != "" {
+				t.Fatalf("e.Delete(...): -want condition reason, +got condition reason: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package request
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	"github.com/crossplane-contrib/provider-http/internal/json"
+)
+
+const (
+	reasonDriftDetected = "DriftDetected"
+
+	// maxDriftPaths and maxDriftPathLength bound the size of status.drift.
+	maxDriftPaths      = 30
+	maxDriftPathLength = 256
+)
+
+// driftStatus returns the drift written to the status, nil if there is none.
+// Paths beyond maxDriftPaths are left out and long paths are cut.
+func driftStatus(diff json.Diff) *v1alpha1.Drift {
+	if diff.Empty() {
+		return nil
+	}
+
+	drift := &v1alpha1.Drift{}
+	remaining := maxDriftPaths
+	truncate := func(paths []string) []string {
+		var kept []string
+		for _, p := range paths {
+			if remaining == 0 {
+				drift.Truncated = true
+				break
+			}
+			if len(p) > maxDriftPathLength {
+				p = truncatePath(p)
+				drift.Truncated = true
+			}
+			kept = append(kept, p)
+			remaining--
+		}
+		return kept
+	}
+
+	drift.Changed = truncate(diff.Changed)
+	drift.Added = truncate(diff.Added)
+	drift.Removed = truncate(diff.Removed)
+	return drift
+}
+
+// truncatePath cuts p to maxDriftPathLength bytes at most, without splitting
+// a character.
+func truncatePath(p string) string {
+	n := maxDriftPathLength
+	for n > 0 && !utf8.RuneStart(p[n]) {
+		n--
+	}
+	return p[:n] + "..."
+}
+
+// shouldReportDrift tells whether the drift of cr is reported in an event,
+// given the drift previously in its status. Only new drifted paths are
+// reported, not each poll finding the same ones. The drift of resources only
+// observed is never corrected, so it is only kept in their status.
+func shouldReportDrift(cr *v1alpha1.Request, previous *v1alpha1.Drift) bool {
+	return cr.Status.Drift != nil && !isObserveOnly(cr) && !reflect.DeepEqual(cr.Status.Drift, previous)
+}
+
+// driftMessage describes drift in an event message.
+func driftMessage(drift *v1alpha1.Drift) string {
+	var parts []string
+	for _, p := range []struct {
+		name  string
+		paths []string
+	}{
+		{"changed", drift.Changed},
+		{"added", drift.Added},
+		{"removed", drift.Removed},
+	} {
+		if len(p.paths) > 0 {
+			parts = append(parts, fmt.Sprintf("%s %s", p.name, strings.Join(p.paths, ", ")))
+		}
+	}
+
+	msg := "Observed state differs from the desired state: " + strings.Join(parts, "; ")
+	if drift.Truncated {
+		msg += " (truncated)"
+	}
+	return msg
+}
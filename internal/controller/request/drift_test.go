@@ -0,0 +1,150 @@
+package request
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	"github.com/crossplane-contrib/provider-http/internal/json"
+)
+
+func Test_driftStatus(t *testing.T) {
+	manyPaths := make([]string, maxDriftPaths+5)
+	for i := range manyPaths {
+		manyPaths[i] = fmt.Sprintf(".items[%d]", i)
+	}
+	longPath := "." + strings.Repeat("a", maxDriftPathLength+10)
+	// A multi-byte character straddles the length limit.
+	longUnicodePath := "." + strings.Repeat("a", maxDriftPathLength-2) + "é" + strings.Repeat("b", 10)
+
+	type args struct {
+		diff json.Diff
+	}
+	type want struct {
+		drift   *v1alpha1.Drift
+		message string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoDrift": {
+			args: args{},
+			want: want{},
+		},
+		"Drift": {
+			args: args{
+				diff: json.Diff{
+					Added:   []string{".email"},
+					Removed: []string{".settings.updatedAt"},
+					Changed: []string{".username", ".settings.theme"},
+				},
+			},
+			want: want{
+				drift: &v1alpha1.Drift{
+					Added:   []string{".email"},
+					Removed: []string{".settings.updatedAt"},
+					Changed: []string{".username", ".settings.theme"},
+				},
+				message: "Observed state differs from the desired state: changed .username, .settings.theme; added .email; removed .settings.updatedAt",
+			},
+		},
+		"Truncated": {
+			args: args{
+				diff: json.Diff{
+					Changed: []string{longPath},
+					Added:   manyPaths,
+					Removed: []string{".id"},
+				},
+			},
+			want: want{
+				drift: &v1alpha1.Drift{
+					Changed:   []string{longPath[:maxDriftPathLength] + "..."},
+					Added:     manyPaths[:maxDriftPaths-1],
+					Truncated: true,
+				},
+			},
+		},
+		"TruncatedOnCharacter": {
+			args: args{
+				diff: json.Diff{
+					Changed: []string{longUnicodePath},
+				},
+			},
+			want: want{
+				drift: &v1alpha1.Drift{
+					Changed:   []string{longUnicodePath[:maxDriftPathLength-1] + "..."},
+					Truncated: true,
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := driftStatus(tc.args.diff)
+			if diff := cmp.Diff(tc.want.drift, got); diff != "" {
+				t.Fatalf("driftStatus(...): -want drift, +got drift: %s", diff)
+			}
+
+			if tc.want.message != "" {
+				if diff := cmp.Diff(tc.want.message, driftMessage(got)); diff != "" {
+					t.Fatalf("driftMessage(...): -want message, +got message: %s", diff)
+				}
+			}
+		})
+	}
+}
+
+func Test_shouldReportDrift(t *testing.T) {
+	drift := &v1alpha1.Drift{Changed: []string{".username"}}
+
+	cases := map[string]struct {
+		policy   xpv1.ManagementPolicy
+		drift    *v1alpha1.Drift
+		previous *v1alpha1.Drift
+		want     bool
+	}{
+		"NoDrift": {
+			policy: xpv1.ManagementFullControl,
+			want:   false,
+		},
+		"Drift": {
+			policy: xpv1.ManagementFullControl,
+			drift:  drift,
+			want:   true,
+		},
+		"ObserveOnly": {
+			policy: xpv1.ManagementObserveOnly,
+			drift:  drift,
+			want:   false,
+		},
+		"SameDrift": {
+			policy:   xpv1.ManagementFullControl,
+			drift:    drift,
+			previous: &v1alpha1.Drift{Changed: []string{".username"}},
+			want:     false,
+		},
+		"OtherDrift": {
+			policy:   xpv1.ManagementFullControl,
+			drift:    drift,
+			previous: &v1alpha1.Drift{Changed: []string{".email"}},
+			want:     true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := httpRequest(func(r *v1alpha1.Request) {
+				r.Spec.ManagementPolicy = tc.policy
+				r.Status.Drift = tc.drift
+			})
+
+			if diff := cmp.Diff(tc.want, shouldReportDrift(cr, tc.previous)); diff != "" {
+				t.Fatalf("shouldReportDrift(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
@@ -6,8 +6,11 @@ import (
 	"strings"
 
 	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/clients/providerconfig"
 	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
+	"github.com/crossplane-contrib/provider-http/internal/jq"
 	"github.com/crossplane-contrib/provider-http/internal/json"
 	"github.com/crossplane-contrib/provider-http/internal/utils"
 	"github.com/pkg/errors"
@@ -16,14 +19,27 @@ import (
 const (
 	errObjectNotFound = "object wasn't found"
 	errNotValidJSON   = "%s is not a valid JSON string: %s"
+	errDesiredNotJSON = "desired state is not a valid JSON string"
+	errExistenceCheck = "cannot evaluate the %s expression of the GET mapping"
+	errUpToDateCheck  = "cannot evaluate the isUpToDate expression"
+	errComparison     = "cannot apply the comparison rules to the %s"
+	existsWhenField   = "existsWhen"
+	notFoundWhenField = "notFoundWhen"
 )
 
 type ObserveRequestDetails struct {
 	Details       httpClient.HttpDetails
 	ResponseError error
 	Synced        bool
+
+	// Drift lists why a successful response is not up to date.
+	Drift json.Diff
 }
 
+// wholeBodyDrift is the drift of responses that cannot be compared field by
+// field.
+var wholeBodyDrift = json.Diff{Changed: []string{"."}}
+
 // NewObserveRequestDetails is a constructor function that initializes
 // an instance of ObserveRequestDetails with default values.
 func NewObserve(details httpClient.HttpDetails, resErr error, synced bool) ObserveRequestDetails {
@@ -48,36 +64,151 @@ func (c *external) isUpToDate(ctx context.Context, cr *v1alpha1.Request) (Observ
 		return FailedObserve(), errors.New(errObjectNotFound)
 	}
 
-	requestDetails, err := c.requestDetails(cr, http.MethodGet)
+	requestDetails, err := c.requestDetails(ctx, cr, http.MethodGet)
+	if err != nil {
+		return FailedObserve(), err
+	}
+
+	mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, http.MethodGet)
+	if ok {
+		ctx = httpClient.ContextWithRetryOverride(ctx, providerconfig.RetryOverride(mapping.Retry))
+
+		signer, err := providerconfig.Signer(ctx, c.localKube, mapping.HMACSigner)
+		if err != nil {
+			return FailedObserve(), errors.Wrap(err, errMappingSigner)
+		}
+		ctx = httpClient.ContextWithSigner(ctx, signer)
+	}
+
+	ctx, err = c.withSecretValues(ctx, requestDetails)
 	if err != nil {
 		return FailedObserve(), err
 	}
 
 	details, responseErr := c.http.SendRequest(ctx, http.MethodGet, requestDetails.Url, requestDetails.Body, requestDetails.Headers, cr.Spec.ForProvider.InsecureSkipTLSVerify)
-	if details.HttpResponse.StatusCode == http.StatusNotFound {
-		return FailedObserve(), errors.New(errObjectNotFound)
+	if httpClient.IsRateLimited(responseErr) {
+		return FailedObserve(), responseErr
+	}
+
+	if httpClient.IsCircuitOpen(responseErr) {
+		cr.Status.SetConditions(apisv1alpha1.CircuitOpen(responseErr))
+		return FailedObserve(), responseErr
 	}
 
-	desiredState, err := c.desiredState(cr)
+	if responseErr == nil {
+		exists, err := resourceExists(mapping, details.HttpResponse)
+		if err != nil {
+			return FailedObserve(), err
+		}
+		if !exists {
+			return FailedObserve(), errors.New(errObjectNotFound)
+		}
+	}
+
+	desiredState, err := c.desiredState(ctx, cr)
 	if err != nil {
 		return FailedObserve(), err
 	}
 
-	return c.compareResponseAndDesiredState(details, responseErr, desiredState)
+	if cr.Spec.ForProvider.IsUpToDate != "" {
+		return c.evaluateUpToDate(cr.Spec.ForProvider.IsUpToDate, details, responseErr, desiredState)
+	}
+
+	return c.compareResponseAndDesiredState(details, responseErr, desiredState, cr.Spec.ForProvider.Comparison)
+}
+
+// evaluateUpToDate decides whether the observed state is up to date with the
+// isUpToDate expression, given the desired state and the GET response.
+func (c *external) evaluateUpToDate(expression string, details httpClient.HttpDetails, err error, desiredState string) (ObserveRequestDetails, error) {
+	observeRequestDetails := NewObserve(details, err, false)
+	if err != nil || !utils.IsHTTPSuccess(details.HttpResponse.StatusCode) {
+		return observeRequestDetails, nil
+	}
+
+	var desired interface{} = desiredState
+	if json.IsJSONString(desiredState) {
+		desired = json.JsonStringToMap(desiredState)
+	}
+
+	synced, err := jq.ParseBool(expression, map[string]interface{}{
+		"desiredState": desired,
+		"response":     utils.ResponseObject(details.HttpResponse),
+	})
+	if err != nil {
+		return FailedObserve(), errors.Wrap(err, errUpToDateCheck)
+	}
+
+	observeRequestDetails.Synced = synced
+	if !synced {
+		observeRequestDetails.Drift = wholeBodyDrift
+	}
+	return observeRequestDetails, nil
 }
 
 func (c *external) isObjectValidForObservation(cr *v1alpha1.Request) bool {
-	return cr.Status.Response.Body != "" &&
-		!(cr.Status.RequestDetails.Method == http.MethodPost && utils.IsHTTPError(cr.Status.Response.StatusCode))
+	if cr.Status.RequestDetails.Method == http.MethodPost && utils.IsHTTPError(cr.Status.Response.StatusCode) {
+		return false
+	}
+
+	// APIs checked with an existence expression may answer with an empty
+	// body, any response shows the resource was requested.
+	if mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, http.MethodGet); ok && hasExistenceCheck(mapping) {
+		return cr.Status.Response.StatusCode != 0
+	}
+
+	return cr.Status.Response.Body != ""
+}
+
+func hasExistenceCheck(mapping *v1alpha1.Mapping) bool {
+	return mapping.ExistsWhen != "" || mapping.NotFoundWhen != ""
 }
 
-func (c *external) compareResponseAndDesiredState(details httpClient.HttpDetails, err error, desiredState string) (ObserveRequestDetails, error) {
+// resourceExists tells whether the response of the GET mapping shows that the
+// resource exists, following its existsWhen and notFoundWhen expressions when
+// set, and treating a 404 as not found otherwise.
+func resourceExists(mapping *v1alpha1.Mapping, response httpClient.HttpResponse) (bool, error) {
+	if mapping == nil || !hasExistenceCheck(mapping) {
+		return response.StatusCode != http.StatusNotFound, nil
+	}
+
+	obj := utils.ResponseObject(response)
+
+	if mapping.ExistsWhen != "" {
+		exists, err := jq.ParseBool(mapping.ExistsWhen, obj)
+		if err != nil {
+			return false, errors.Wrapf(err, errExistenceCheck, existsWhenField)
+		}
+		if !exists {
+			return false, nil
+		}
+	}
+
+	if mapping.NotFoundWhen != "" {
+		notFound, err := jq.ParseBool(mapping.NotFoundWhen, obj)
+		if err != nil {
+			return false, errors.Wrapf(err, errExistenceCheck, notFoundWhenField)
+		}
+		if notFound {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func (c *external) compareResponseAndDesiredState(details httpClient.HttpDetails, err error, desiredState string, comparison *v1alpha1.Comparison) (ObserveRequestDetails, error) {
 	observeRequestDetails := NewObserve(details, err, false)
 
 	if json.IsJSONString(details.HttpResponse.Body) && json.IsJSONString(desiredState) {
-		responseBodyMap := json.JsonStringToMap(details.HttpResponse.Body)
-		desiredStateMap := json.JsonStringToMap(desiredState)
-		observeRequestDetails.Synced = json.Contains(responseBodyMap, desiredStateMap) && utils.IsHTTPSuccess(details.HttpResponse.StatusCode)
+		responseBodyMap, desiredStateMap, err := normalizeForComparison(comparison, json.JsonStringToMap(details.HttpResponse.Body), json.JsonStringToMap(desiredState))
+		if err != nil {
+			return FailedObserve(), err
+		}
+		drift := json.ContainsDiff(responseBodyMap, desiredStateMap)
+		observeRequestDetails.Synced = drift.Empty() && utils.IsHTTPSuccess(details.HttpResponse.StatusCode)
+		if utils.IsHTTPSuccess(details.HttpResponse.StatusCode) {
+			observeRequestDetails.Drift = drift
+		}
 		return observeRequestDetails, nil
 	}
 
@@ -86,23 +217,64 @@ func (c *external) compareResponseAndDesiredState(details httpClient.HttpDetails
 	}
 
 	if json.IsJSONString(details.HttpResponse.Body) && !json.IsJSONString(desiredState) {
-		return FailedObserve(), errors.Errorf(errNotValidJSON, "PUT mapping result", desiredState)
+		// The desired state may hold Secret values.
+		return FailedObserve(), errors.New(errDesiredNotJSON)
 	}
 
 	observeRequestDetails.Synced = strings.Contains(details.HttpResponse.Body, desiredState) && utils.IsHTTPSuccess(details.HttpResponse.StatusCode)
+	if !observeRequestDetails.Synced && utils.IsHTTPSuccess(details.HttpResponse.StatusCode) {
+		observeRequestDetails.Drift = wholeBodyDrift
+	}
 	return observeRequestDetails, nil
 }
 
-func (c *external) desiredState(cr *v1alpha1.Request) (string, error) {
-	requestDetails, err := c.requestDetails(cr, http.MethodPut)
-	return requestDetails.Body, err
+// normalizeForComparison applies the comparison rules to the response body and
+// the desired state.
+func normalizeForComparison(comparison *v1alpha1.Comparison, responseBody, desiredState map[string]interface{}) (map[string]interface{}, map[string]interface{}, error) {
+	if comparison == nil {
+		return responseBody, desiredState, nil
+	}
+
+	normalizer, err := json.NewNormalizer(json.CompareOptions{
+		Normalize:    comparison.Normalize,
+		IgnorePaths:  comparison.IgnorePaths,
+		ArraysAsSets: comparison.ArraysAsSets,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if responseBody, err = normalizer.Normalize(responseBody); err != nil {
+		return nil, nil, errors.Wrapf(err, errComparison, "response body")
+	}
+	if desiredState, err = normalizer.Normalize(desiredState); err != nil {
+		return nil, nil, errors.Wrapf(err, errComparison, "desired state")
+	}
+
+	return responseBody, desiredState, nil
+}
+
+// desiredState returns the body of the update mapping, with its Secret
+// references resolved so it can be compared with the observed state.
+func (c *external) desiredState(ctx context.Context, cr *v1alpha1.Request) (string, error) {
+	requestDetails, err := c.requestDetails(ctx, cr, updateMethod(&cr.Spec.ForProvider))
+	if err != nil {
+		return "", err
+	}
+
+	values, err := c.secrets.Resolve(ctx, requestDetails.Body)
+	if err != nil {
+		return "", errors.Wrap(err, errResolveSecrets)
+	}
+
+	return httpClient.InterpolateBody(values, requestDetails.Body), nil
 }
 
-func (c *external) requestDetails(cr *v1alpha1.Request, method string) (requestgen.RequestDetails, error) {
+func (c *external) requestDetails(ctx context.Context, cr *v1alpha1.Request, method string) (requestgen.RequestDetails, error) {
 	mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, method)
 	if !ok {
 		return requestgen.RequestDetails{}, errors.Errorf(errMappingNotFound, method)
 	}
 
-	return generateValidRequestDetails(cr, mapping)
+	return c.generateValidRequestDetails(ctx, cr, mapping)
 }
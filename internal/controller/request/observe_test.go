@@ -3,14 +3,20 @@ package request
 import (
 	"context"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
 	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/json"
+	"github.com/crossplane-contrib/provider-http/internal/secrets"
+	"github.com/crossplane-contrib/provider-http/internal/utils"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -88,6 +94,33 @@ func Test_isUpToDate(t *testing.T) {
 				err: errNotFound,
 			},
 		},
+		"ObjectNotFoundExistsWhen": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{
+								Body:       `{"items":[]}`,
+								StatusCode: 200,
+							},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.Mappings = []v1alpha1.Mapping{
+						testPostMapping,
+						{Method: http.MethodGet, URL: ".payload.baseUrl", ExistsWhen: "(.body.items | length) > 0"},
+					}
+					r.Status.Response.StatusCode = 201
+				}),
+			},
+			want: want{
+				err: errNotFound,
+			},
+		},
 		"FailBodyNotJSON": {
 			args: args{
 				http: &MockHttpClient{
@@ -141,9 +174,134 @@ func Test_isUpToDate(t *testing.T) {
 					},
 					ResponseError: nil,
 					Synced:        false,
+					Drift:         json.Diff{Changed: []string{".username"}},
+				},
+			},
+		},
+		"ComparisonRulesSynced": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{
+								Body:       `{"username":"John_Doe_New_Username","updatedAt":"2024-01-02"}`,
+								StatusCode: 200,
+							},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.Comparison = &v1alpha1.Comparison{
+						Normalize:   ".username |= ascii_downcase",
+						IgnorePaths: []string{".updatedAt"},
+					}
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+			},
+			want: want{
+				result: ObserveRequestDetails{
+					Details: httpClient.HttpDetails{
+						HttpResponse: httpClient.HttpResponse{
+							Body:       `{"username":"John_Doe_New_Username","updatedAt":"2024-01-02"}`,
+							StatusCode: 200,
+						},
+					},
+					Synced: true,
+				},
+			},
+		},
+		"IsUpToDateExpressionSynced": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{
+								Body:       `{"user":{"name":"john_doe_new_username"}}`,
+								StatusCode: 200,
+							},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.IsUpToDate = ".response.body.user.name == .desiredState.username"
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+			},
+			want: want{
+				result: ObserveRequestDetails{
+					Details: httpClient.HttpDetails{
+						HttpResponse: httpClient.HttpResponse{
+							Body:       `{"user":{"name":"john_doe_new_username"}}`,
+							StatusCode: 200,
+						},
+					},
+					Synced: true,
+				},
+			},
+		},
+		"IsUpToDateExpressionNotSynced": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{
+								Body:       `{"user":{"name":"old_name"}}`,
+								StatusCode: 200,
+							},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.IsUpToDate = ".response.body.user.name == .desiredState.username"
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+			},
+			want: want{
+				result: ObserveRequestDetails{
+					Details: httpClient.HttpDetails{
+						HttpResponse: httpClient.HttpResponse{
+							Body:       `{"user":{"name":"old_name"}}`,
+							StatusCode: 200,
+						},
+					},
+					Synced: false,
+					Drift:  wholeBodyDrift,
 				},
 			},
 		},
+		"IsUpToDateExpressionNotBoolean": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{
+								Body:       `{"user":{"name":"old_name"}}`,
+								StatusCode: 200,
+							},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.IsUpToDate = ".response.body.user.name"
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+			},
+			want: want{
+				err: errors.Wrap(errors.Errorf("failed to parse string: %s", "old_name"), errUpToDateCheck),
+			},
+		},
 		"SuccessJSONBody": {
 			args: args{
 				http: &MockHttpClient{
@@ -199,3 +357,171 @@ func Test_isUpToDate(t *testing.T) {
 		})
 	}
 }
+
+// Test_isUpToDateSecretValues proves that the Secret values of the desired
+// state never reach the errors of the comparison.
+func Test_isUpToDateSecretValues(t *testing.T) {
+	kube := &test.MockClient{
+		MockGet: func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+			obj.(*corev1.Secret).Data = map[string][]byte{"token": []byte("s3cr3t-token")}
+			return nil
+		},
+	}
+	mg := httpRequest(func(r *v1alpha1.Request) {
+		r.Spec.ForProvider.Mappings = []v1alpha1.Mapping{
+			testPostMapping,
+			testGetMapping,
+			{Method: http.MethodPut, URL: testPutMapping.URL, Body: `"token={{ secret:default:api:token }}"`},
+		}
+		r.Status.Response.Body = `{"id":"123"}`
+		r.Status.Response.StatusCode = 200
+	})
+
+	e := &external{
+		localKube: kube,
+		logger:    logging.NewNopLogger(),
+		http: &MockHttpClient{
+			MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+				return httpClient.HttpDetails{
+					HttpResponse: httpClient.HttpResponse{Body: `{"token":"other"}`, StatusCode: 200},
+				}, nil
+			},
+		},
+		secrets: secrets.Resolver{Namespace: "default", Reader: kube},
+	}
+
+	_, err := e.isUpToDate(context.Background(), mg)
+	if diff := cmp.Diff(errors.New(errDesiredNotJSON), err, test.EquateErrors()); diff != "" {
+		t.Fatalf("isUpToDate(...): -want error, +got error: %s", diff)
+	}
+	if strings.Contains(err.Error(), "s3cr3t-token") {
+		t.Fatalf("isUpToDate(...): the secret value reached the error: %s", err)
+	}
+}
+
+// Test_ObserveRateLimited checks that a Request whose GET request was held
+// back by the rate limits is not reported as failed.
+func Test_ObserveRateLimited(t *testing.T) {
+	e := &external{
+		localKube: &test.MockClient{MockGet: test.NewMockGetFn(nil)},
+		logger:    logging.NewNopLogger(),
+		http: &MockHttpClient{
+			MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+				return httpClient.HttpDetails{}, errRateLimited
+			},
+		},
+		requeues: utils.NewRequeues(),
+	}
+	mg := httpRequest(func(r *v1alpha1.Request) {
+		r.Status.Response.Body = `{"id":"123"}`
+		r.Status.Response.StatusCode = 200
+	})
+
+	got, err := e.Observe(context.Background(), mg)
+	if diff := cmp.Diff(nil, err, test.EquateErrors()); diff != "" {
+		t.Fatalf("Observe(...): -want error, +got error: %s", diff)
+	}
+	if diff := cmp.Diff(managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, got); diff != "" {
+		t.Fatalf("Observe(...): -want observation, +got observation: %s", diff)
+	}
+}
+
+func Test_resourceExists(t *testing.T) {
+	type args struct {
+		mapping  *v1alpha1.Mapping
+		response httpClient.HttpResponse
+	}
+	type want struct {
+		exists bool
+		err    error
+	}
+
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"DefaultNotFound": {
+			args: args{
+				mapping:  &testGetMapping,
+				response: httpClient.HttpResponse{StatusCode: http.StatusNotFound},
+			},
+			want: want{
+				exists: false,
+			},
+		},
+		"DefaultGone": {
+			args: args{
+				mapping:  &testGetMapping,
+				response: httpClient.HttpResponse{StatusCode: http.StatusGone},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"ExistsWhenEmptyList": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, ExistsWhen: "(.body.items | length) > 0"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusOK, Body: `{"items":[]}`},
+			},
+			want: want{
+				exists: false,
+			},
+		},
+		"ExistsWhenItems": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, ExistsWhen: "(.body.items | length) > 0"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusOK, Body: `{"items":[{"id":"123"}]}`},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"NotFoundWhenGone": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, NotFoundWhen: ".statusCode == 410 or .body.deleted == true"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusGone},
+			},
+			want: want{
+				exists: false,
+			},
+		},
+		"NotFoundWhenDeleted": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, NotFoundWhen: ".statusCode == 410 or .body.deleted == true"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusOK, Body: `{"deleted":true}`},
+			},
+			want: want{
+				exists: false,
+			},
+		},
+		"NotFoundWhenReplaces404": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, NotFoundWhen: ".body.deleted == true"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusNotFound, Body: `{"deleted":false}`},
+			},
+			want: want{
+				exists: true,
+			},
+		},
+		"NotBoolean": {
+			args: args{
+				mapping:  &v1alpha1.Mapping{Method: http.MethodGet, ExistsWhen: ".statusCode"},
+				response: httpClient.HttpResponse{StatusCode: http.StatusOK},
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("failed to parse string: %s", "200"), errExistenceCheck, existsWhenField),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, gotErr := resourceExists(tc.args.mapping, tc.args.response)
+			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
+				t.Fatalf("resourceExists(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.exists, got); diff != "" {
+				t.Errorf("resourceExists(...): -want exists, +got exists: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package request
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/statushandler"
+	"github.com/crossplane-contrib/provider-http/internal/json"
+)
+
+const (
+	errNoObservedState = "cannot compute the %s without the JSON response of the GET mapping"
+	errCreatePatch     = "cannot compute the %s"
+
+	contentTypeHeader         = "Content-Type"
+	contentTypeJSON           = "application/json"
+	contentTypeMergePatchJSON = "application/merge-patch+json"
+	contentTypeJSONPatchJSON  = "application/json-patch+json"
+)
+
+// patchRequestDetails returns the request details of the PATCH mapping with
+// the body and the Content-Type of its patch type. Computed patches go from
+// the GET response kept in the status by Observe to the rendered body.
+func (c *external) patchRequestDetails(ctx context.Context, cr *v1alpha1.Request, mapping *v1alpha1.Mapping, requestDetails requestgen.RequestDetails) (requestgen.RequestDetails, error) {
+	var createPatch func(observed, desired map[string]interface{}) ([]byte, error)
+	var contentType string
+
+	switch mapping.PatchType {
+	case v1alpha1.PatchTypeMergePatch:
+		createPatch, contentType = json.MergePatch, contentTypeMergePatchJSON
+	case v1alpha1.PatchTypeJSONPatch:
+		createPatch, contentType = json.JSONPatch, contentTypeJSONPatchJSON
+	default:
+		if !hasHeader(requestDetails.Headers, contentTypeHeader) {
+			requestDetails.Headers = withHeader(requestDetails.Headers, contentTypeHeader, contentTypeJSON)
+		}
+		return requestDetails, nil
+	}
+
+	response, _, err := statushandler.UnmaskedResponses(ctx, c.localKube, cr)
+	if err != nil {
+		return requestgen.RequestDetails{}, err
+	}
+
+	if cr.Status.RequestDetails.Method != http.MethodGet || !json.IsJSONString(response.Body) {
+		return requestgen.RequestDetails{}, errors.Errorf(errNoObservedState, mapping.PatchType)
+	}
+
+	if !json.IsJSONString(requestDetails.Body) {
+		return requestgen.RequestDetails{}, errors.Errorf(errNotValidJSON, "PATCH mapping body", requestDetails.Body)
+	}
+
+	patch, err := createPatch(json.JsonStringToMap(response.Body), json.JsonStringToMap(requestDetails.Body))
+	if err != nil {
+		return requestgen.RequestDetails{}, errors.Wrapf(err, errCreatePatch, mapping.PatchType)
+	}
+
+	requestDetails.Body = string(patch)
+	requestDetails.Headers = withHeader(requestDetails.Headers, contentTypeHeader, contentType)
+	return requestDetails, nil
+}
+
+// hasHeader tells whether headers has the header key, matched case
+// insensitively.
+func hasHeader(headers map[string][]string, key string) bool {
+	for k := range headers {
+		if http.CanonicalHeaderKey(k) == http.CanonicalHeaderKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// withHeader returns a copy of headers with the header key set to value.
+func withHeader(headers map[string][]string, key, value string) map[string][]string {
+	copied := make(map[string][]string, len(headers)+1)
+	for k, v := range headers {
+		if http.CanonicalHeaderKey(k) != http.CanonicalHeaderKey(key) {
+			copied[k] = v
+		}
+	}
+	copied[key] = []string{value}
+	return copied
+}
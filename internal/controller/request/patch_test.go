@@ -0,0 +1,177 @@
+package request
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/statushandler"
+)
+
+func Test_patchRequestDetails(t *testing.T) {
+	observed := func(r *v1alpha1.Request) {
+		r.Status.RequestDetails.Method = http.MethodGet
+		r.Status.Response.Body = `{"id":"123","username":"john_doe","email":"john.doe@example.com"}`
+	}
+	desired := requestgen.RequestDetails{
+		Url:     "https://api.example.com/users/123",
+		Body:    `{"username":"john_doe_new_username","email":"john.doe@example.com"}`,
+		Headers: map[string][]string{"Authorization": {"Bearer token"}},
+	}
+
+	maskedObserved := func(r *v1alpha1.Request) {
+		r.Status.RequestDetails.Method = http.MethodGet
+		r.Status.Response.Body = `{"id":"123","username":"john_doe","email":"***"}`
+		r.Status.Masked = &v1alpha1.Masked{Response: []string{"body"}, SecretRef: testUnmaskedSecretRef}
+	}
+
+	type args struct {
+		mg             *v1alpha1.Request
+		localKube      client.Client
+		patchType      v1alpha1.PatchType
+		requestDetails requestgen.RequestDetails
+	}
+	type want struct {
+		requestDetails requestgen.RequestDetails
+		err            error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Body": {
+			args: args{
+				mg:             httpRequest(),
+				requestDetails: desired,
+			},
+			want: want{
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    desired.Body,
+					Headers: map[string][]string{"Authorization": {"Bearer token"}, "Content-Type": {"application/json"}},
+				},
+			},
+		},
+		"BodyWithContentType": {
+			args: args{
+				mg:        httpRequest(),
+				patchType: v1alpha1.PatchTypeBody,
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    desired.Body,
+					Headers: map[string][]string{"content-type": {"application/vnd.api+json"}},
+				},
+			},
+			want: want{
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    desired.Body,
+					Headers: map[string][]string{"content-type": {"application/vnd.api+json"}},
+				},
+			},
+		},
+		"MergePatch": {
+			args: args{
+				mg:             httpRequest(observed),
+				patchType:      v1alpha1.PatchTypeMergePatch,
+				requestDetails: desired,
+			},
+			want: want{
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    `{"username":"john_doe_new_username"}`,
+					Headers: map[string][]string{"Authorization": {"Bearer token"}, "Content-Type": {"application/merge-patch+json"}},
+				},
+			},
+		},
+		"JSONPatch": {
+			args: args{
+				mg:             httpRequest(observed),
+				patchType:      v1alpha1.PatchTypeJSONPatch,
+				requestDetails: desired,
+			},
+			want: want{
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    `[{"op":"replace","path":"/username","value":"john_doe_new_username"}]`,
+					Headers: map[string][]string{"Authorization": {"Bearer token"}, "Content-Type": {"application/json-patch+json"}},
+				},
+			},
+		},
+		"NoObservedState": {
+			args: args{
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Status.RequestDetails.Method = http.MethodPut
+					r.Status.Response.Body = `{"id":"123"}`
+				}),
+				patchType:      v1alpha1.PatchTypeMergePatch,
+				requestDetails: desired,
+			},
+			want: want{
+				err: errors.Errorf(errNoObservedState, v1alpha1.PatchTypeMergePatch),
+			},
+		},
+		"MaskedObservedState": {
+			args: args{
+				mg:             httpRequest(maskedObserved),
+				localKube:      &test.MockClient{MockGet: unmaskedSecret(`{"id":"123","username":"john_doe","email":"john.doe@example.com"}`)},
+				patchType:      v1alpha1.PatchTypeMergePatch,
+				requestDetails: desired,
+			},
+			want: want{
+				requestDetails: requestgen.RequestDetails{
+					Url:     desired.Url,
+					Body:    `{"username":"john_doe_new_username"}`,
+					Headers: map[string][]string{"Authorization": {"Bearer token"}, "Content-Type": {"application/merge-patch+json"}},
+				},
+			},
+		},
+		"MaskedValuesLost": {
+			args: args{
+				mg:             httpRequest(maskedObserved),
+				localKube:      &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, testUnmaskedSecretRef.Name))},
+				patchType:      v1alpha1.PatchTypeMergePatch,
+				requestDetails: desired,
+			},
+			want: want{
+				err: errors.New(statushandler.ErrMaskedValuesLost),
+			},
+		},
+		"DesiredStateNotJSON": {
+			args: args{
+				mg:        httpRequest(observed),
+				patchType: v1alpha1.PatchTypeJSONPatch,
+				requestDetails: requestgen.RequestDetails{
+					Url:  desired.Url,
+					Body: "not a JSON",
+				},
+			},
+			want: want{
+				err: errors.Errorf(errNotValidJSON, "PATCH mapping body", "not a JSON"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := &external{localKube: tc.args.localKube}
+			mapping := &v1alpha1.Mapping{Method: http.MethodPatch, PatchType: tc.args.patchType}
+			got, err := e.patchRequestDetails(context.Background(), tc.args.mg, mapping, tc.args.requestDetails)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("patchRequestDetails(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.requestDetails, got); diff != "" {
+				t.Fatalf("patchRequestDetails(...): -want request details, +got request details: %s", diff)
+			}
+		})
+	}
+}
@@ -23,13 +23,18 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/controller"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
@@ -37,8 +42,11 @@ import (
 	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
 	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/clients/providerconfig"
 	"github.com/crossplane-contrib/provider-http/internal/controller/request/requestgen"
 	"github.com/crossplane-contrib/provider-http/internal/controller/request/statushandler"
+	"github.com/crossplane-contrib/provider-http/internal/features"
+	"github.com/crossplane-contrib/provider-http/internal/secrets"
 	"github.com/crossplane-contrib/provider-http/internal/utils"
 )
 
@@ -46,39 +54,62 @@ const (
 	errNotRequest                   = "managed resource is not a Request custom resource"
 	errTrackPCUsage                 = "cannot track ProviderConfig usage"
 	errNewHttpClient                = "cannot create new Http client"
+	errProviderConfigOptions        = "cannot resolve ProviderConfig settings"
 	errProviderNotRetrieved         = "provider could not be retrieved"
 	errFailedToSendHttpRequest      = "something went wrong"
 	errFailedToCheckIfUpToDate      = "failed to check if request is up to date"
 	errFailedToUpdateStatusFailures = "failed to reset status failures counter"
 	errFailedUpdateStatusConditions = "failed updating status conditions"
 	errMappingNotFound              = "%s mapping doesn't exist in request, skipping operation"
+	errMappingSigner                = "cannot resolve mapping HMAC signer"
+	errResolveSecrets               = "cannot resolve secret references"
+	errStatusMasking                = "cannot build status masking rules"
+	errIndexSecretRefs              = "cannot index referenced secrets"
 )
 
-// Setup adds a controller that reconciles Request managed resources.
-func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration) error {
+// Setup adds a controller that reconciles Request managed resources, whose
+// placeholders may reference the Secrets of the given scope.
+func Setup(mgr ctrl.Manager, o controller.Options, timeout time.Duration, scope secrets.Scope) error {
 	name := managed.ControllerName(v1alpha1.RequestGroupKind)
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+	requeues := utils.NewRequeues()
 	cps := []managed.ConnectionPublisher{managed.NewAPISecretPublisher(mgr.GetClient(), mgr.GetScheme())}
 
-	r := managed.NewReconciler(mgr,
-		resource.ManagedKind(v1alpha1.RequestGroupVersionKind),
+	opts := []managed.ReconcilerOption{
 		managed.WithExternalConnecter(&connector{
 			logger:          o.Logger,
 			kube:            mgr.GetClient(),
 			usage:           resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
 			newHttpClientFn: httpClient.NewClient,
+			recorder:        recorder,
+			secrets:         scope.Resolver(),
+			requeues:        requeues,
 		}),
 		managed.WithLogger(o.Logger.WithValues("controller", name)),
 		managed.WithPollInterval(o.PollInterval),
 		managed.WithTimeout(timeout),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))),
-		managed.WithConnectionPublishers(cps...))
+		managed.WithRecorder(recorder),
+		managed.WithConnectionPublishers(cps...),
+		// The external name is only set by users adopting a resource, so
+		// it isn't defaulted to the name of the Request.
+		managed.WithInitializers(),
+	}
+	if o.Features.Enabled(features.EnableAlphaManagementPolicies) {
+		opts = append(opts, managed.WithManagementPolicies())
+	}
+
+	r := managed.NewReconciler(mgr, resource.ManagedKind(v1alpha1.RequestGroupVersionKind), opts...)
+
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &v1alpha1.Request{}, secrets.IndexKey, secrets.IndexReferencedSecrets); err != nil {
+		return errors.Wrap(err, errIndexSecretRefs)
+	}
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o.ForControllerRuntime()).
-		WithEventFilter(resource.DesiredStateChanged()).
-		For(&v1alpha1.Request{}).
-		Complete(ratelimiter.NewReconciler(name, r, o.GlobalRateLimiter))
+		For(&v1alpha1.Request{}, builder.WithPredicates(resource.DesiredStateChanged())).
+		Watches(source.NewKindWithCache(&corev1.Secret{}, scope.Cache), secrets.EnqueueReferencing(mgr.GetClient(), &v1alpha1.RequestList{})).
+		Complete(ratelimiter.NewReconciler(name, requeues.Reconciler(&operationPoller{Reconciler: r, kube: mgr.GetClient()}), o.GlobalRateLimiter))
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
@@ -87,7 +118,10 @@ type connector struct {
 	logger          logging.Logger
 	kube            client.Client
 	usage           resource.Tracker
-	newHttpClientFn func(log logging.Logger, timeout time.Duration) (httpClient.Client, error)
+	newHttpClientFn func(log logging.Logger, timeout time.Duration, opts ...httpClient.Option) (httpClient.Client, error)
+	recorder        event.Recorder
+	secrets         secrets.Resolver
+	requeues        *utils.Requeues
 }
 
 // Connect typically produces an ExternalClient by:
@@ -113,7 +147,17 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		return nil, errors.Wrap(err, errProviderNotRetrieved)
 	}
 
-	h, err := c.newHttpClientFn(l, utils.WaitTimeout(cr.Spec.ForProvider.WaitTimeout))
+	opts, err := providerconfig.ClientOptions(ctx, c.kube, pc, cr.Spec.ForProvider.TLSConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, errProviderConfigOptions)
+	}
+
+	masker, err := providerconfig.StatusMasker(pc.Spec.StatusMasking, cr.Spec.ForProvider.StatusMasking)
+	if err != nil {
+		return nil, errors.Wrap(err, errStatusMasking)
+	}
+
+	h, err := c.newHttpClientFn(l, utils.WaitTimeout(cr.Spec.ForProvider.WaitTimeout), opts...)
 	if err != nil {
 		return nil, errors.Wrap(err, errNewHttpClient)
 	}
@@ -122,6 +166,10 @@ func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.E
 		localKube: c.kube,
 		logger:    l,
 		http:      h,
+		masking:   statushandler.Masking{Masker: masker, Namespace: c.secrets.Namespace},
+		recorder:  c.recorder,
+		secrets:   c.secrets,
+		requeues:  c.requeues,
 	}, nil
 }
 
@@ -131,6 +179,21 @@ type external struct {
 	localKube client.Client
 	logger    logging.Logger
 	http      httpClient.Client
+	masking   statushandler.Masking
+	recorder  event.Recorder
+	secrets   secrets.Resolver
+	requeues  *utils.Requeues
+}
+
+// rateLimited requeues cr once the rate limits that held back its request
+// allow it, telling whether err was caused by them. Such requests were not
+// sent, so they are not reported as errors.
+func (c *external) rateLimited(cr *v1alpha1.Request, err error) bool {
+	after, ok := httpClient.RetryAfter(err)
+	if ok {
+		c.requeues.RequeueAfter(cr, after)
+	}
+	return ok
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -139,6 +202,34 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.New(errNotRequest)
 	}
 
+	observation, err := c.observe(ctx, cr)
+	if c.rateLimited(cr, err) {
+		// Nothing is known of the resource until its request is sent.
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+	return observation, err
+}
+
+func (c *external) observe(ctx context.Context, cr *v1alpha1.Request) (managed.ExternalObservation, error) {
+	if cr.Status.AsyncOperation != nil {
+		pending, err := c.observeAsyncOperation(ctx, cr)
+		if err != nil {
+			return managed.ExternalObservation{}, err
+		}
+		if pending {
+			return managed.ExternalObservation{
+				ResourceExists:   true,
+				ResourceUpToDate: true,
+			}, nil
+		}
+	}
+
+	if c.shouldAdopt(cr) {
+		if _, err := c.adopt(ctx, cr); err != nil {
+			return managed.ExternalObservation{}, errors.Wrap(err, errAdopt)
+		}
+	}
+
 	observeRequestDetails, err := c.isUpToDate(ctx, cr)
 	if err != nil && err.Error() == errObjectNotFound {
 		return managed.ExternalObservation{
@@ -155,7 +246,7 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 		return managed.ExternalObservation{}, errors.Wrap(err, "failed to get the latest version of the resource")
 	}
 
-	statusHandler, err := statushandler.NewStatusHandler(ctx, cr, observeRequestDetails.Details, observeRequestDetails.ResponseError, c.localKube, c.logger)
+	statusHandler, err := statushandler.NewStatusHandler(ctx, cr, observeRequestDetails.Details, observeRequestDetails.ResponseError, c.localKube, c.logger, c.masking)
 	if err != nil {
 		return managed.ExternalObservation{}, err
 	}
@@ -166,38 +257,84 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	}
 
 	cr.Status.SetConditions(xpv1.Available())
+	utils.SetCircuitClosed(cr)
+	previousDrift := cr.Status.Drift
+	cr.Status.Drift = driftStatus(observeRequestDetails.Drift)
+	if c.recorder != nil && shouldReportDrift(cr, previousDrift) {
+		// Reported before the Update the drift triggers.
+		c.recorder.Event(cr, event.Normal(reasonDriftDetected, driftMessage(cr.Status.Drift)))
+	}
+
 	err = statusHandler.SetRequestStatus()
 	if err != nil {
 		return managed.ExternalObservation{}, errors.Wrap(err, " failed updating status")
 	}
 
+	var connectionDetails managed.ConnectionDetails
+	if response := observeRequestDetails.Details.HttpResponse; observeRequestDetails.ResponseError == nil && utils.IsHTTPSuccess(response.StatusCode) {
+		if connectionDetails, err = utils.ConnectionDetails(cr.Spec.ForProvider.ConnectionDetails, response); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+
 	return managed.ExternalObservation{
 		ResourceExists:    true,
 		ResourceUpToDate:  synced,
-		ConnectionDetails: nil,
+		ConnectionDetails: connectionDetails,
 	}, nil
 }
 
-func (c *external) deployAction(ctx context.Context, cr *v1alpha1.Request, method string) error {
+// deployAction sends the request of the mapping of method and returns the
+// connection details extracted from its response.
+func (c *external) deployAction(ctx context.Context, cr *v1alpha1.Request, method string) (managed.ConnectionDetails, error) {
 	mapping, ok := getMappingByMethod(&cr.Spec.ForProvider, method)
 	if !ok {
 		c.logger.Info(errMappingNotFound, method)
-		return nil
+		return nil, nil
 	}
 
-	requestDetails, err := generateValidRequestDetails(cr, mapping)
+	requestDetails, err := c.generateValidRequestDetails(ctx, cr, mapping)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	if mapping.Method == http.MethodPatch {
+		if requestDetails, err = c.patchRequestDetails(ctx, cr, mapping, requestDetails); err != nil {
+			return nil, err
+		}
+	}
+
+	ctx, err = c.mappingContext(ctx, mapping, requestDetails)
+	if err != nil {
+		return nil, err
 	}
 
 	details, err := c.http.SendRequest(ctx, mapping.Method, requestDetails.Url, requestDetails.Body, requestDetails.Headers, cr.Spec.ForProvider.InsecureSkipTLSVerify)
+	if httpClient.IsRateLimited(err) {
+		return nil, err
+	}
+
+	if httpClient.IsCircuitOpen(err) {
+		cr.Status.SetConditions(apisv1alpha1.CircuitOpen(err))
+		return nil, err
+	}
 
-	statusHandler, err := statushandler.NewStatusHandler(ctx, cr, details, err, c.localKube, c.logger)
+	statusHandler, err := statushandler.NewStatusHandler(ctx, cr, details, err, c.localKube, c.logger, c.masking)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return statusHandler.SetRequestStatus()
+	utils.SetCircuitClosed(cr)
+
+	if err := statusHandler.SetRequestStatus(); err != nil {
+		return nil, err
+	}
+
+	if err := c.startAsyncOperation(ctx, cr, mapping, details); err != nil {
+		return nil, err
+	}
+
+	return utils.ConnectionDetails(cr.Spec.ForProvider.ConnectionDetails, details.HttpResponse)
 }
 
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
@@ -206,7 +343,24 @@ func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalCreation{}, errors.New(errNotRequest)
 	}
 
-	return managed.ExternalCreation{}, errors.Wrap(c.deployAction(ctx, cr, http.MethodPost), errFailedToSendHttpRequest)
+	connectionDetails, err := c.deployAction(ctx, cr, http.MethodPost)
+	if c.rateLimited(cr, err) {
+		return managed.ExternalCreation{}, nil
+	}
+
+	if err != nil && shouldAdoptOnConflict(cr) {
+		adopted, adoptErr := c.adopt(ctx, cr)
+		if c.rateLimited(cr, adoptErr) {
+			return managed.ExternalCreation{}, nil
+		}
+		if adoptErr != nil {
+			return managed.ExternalCreation{}, errors.Wrap(adoptErr, errAdopt)
+		}
+		if adopted {
+			return managed.ExternalCreation{}, nil
+		}
+	}
+	return managed.ExternalCreation{ConnectionDetails: connectionDetails}, errors.Wrap(err, errFailedToSendHttpRequest)
 }
 
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
@@ -215,7 +369,11 @@ func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.Ext
 		return managed.ExternalUpdate{}, errors.New(errNotRequest)
 	}
 
-	return managed.ExternalUpdate{}, errors.Wrap(c.deployAction(ctx, cr, http.MethodPut), errFailedToSendHttpRequest)
+	connectionDetails, err := c.deployAction(ctx, cr, updateMethod(&cr.Spec.ForProvider))
+	if c.rateLimited(cr, err) {
+		return managed.ExternalUpdate{}, nil
+	}
+	return managed.ExternalUpdate{ConnectionDetails: connectionDetails}, errors.Wrap(err, errFailedToSendHttpRequest)
 }
 
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
@@ -224,21 +382,78 @@ func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 		return errors.New(errNotRequest)
 	}
 
-	return errors.Wrap(c.deployAction(ctx, cr, http.MethodDelete), errFailedToSendHttpRequest)
+	if cr.Status.AsyncOperation != nil {
+		// Sent once the operation the resource waits for finishes.
+		return nil
+	}
+
+	verification := cr.Spec.ForProvider.DeletionVerification
+	if verification != nil && cr.Status.DeletionRequested != nil {
+		// Observe found the resource still exists.
+		return verifyDeletion(cr, verification)
+	}
+
+	_, err := c.deployAction(ctx, cr, http.MethodDelete)
+	if c.rateLimited(cr, err) {
+		return nil
+	}
+	if err == nil && verification != nil {
+		requested := metav1.Now()
+		cr.Status.DeletionRequested = &requested
+	}
+	return errors.Wrap(err, errFailedToSendHttpRequest)
+}
+
+// mappingContext returns a context whose requests follow the retry policy and
+// the signer of mapping, and have the Secret references of requestDetails
+// resolved.
+func (c *external) mappingContext(ctx context.Context, mapping *v1alpha1.Mapping, requestDetails requestgen.RequestDetails) (context.Context, error) {
+	ctx = httpClient.ContextWithRetryOverride(ctx, providerconfig.RetryOverride(mapping.Retry))
+
+	signer, err := providerconfig.Signer(ctx, c.localKube, mapping.HMACSigner)
+	if err != nil {
+		return ctx, errors.Wrap(err, errMappingSigner)
+	}
+	ctx = httpClient.ContextWithSigner(ctx, signer)
+
+	return c.withSecretValues(ctx, requestDetails)
+}
+
+// withSecretValues returns a context whose requests have the Secret references
+// of requestDetails replaced with their values when they are sent. The values
+// never reach the status or the logs.
+func (c *external) withSecretValues(ctx context.Context, requestDetails requestgen.RequestDetails) (context.Context, error) {
+	values, err := c.secrets.Resolve(ctx, secrets.RequestStrings(requestDetails.Url, requestDetails.Body, requestDetails.Headers)...)
+	if err != nil {
+		return ctx, errors.Wrap(err, errResolveSecrets)
+	}
+
+	if values == nil {
+		return ctx, nil
+	}
+
+	return httpClient.ContextWithInterpolator(ctx, values), nil
 }
 
 // generateValidRequestDetails generates valid request details based on the given Request resource and Mapping configuration.
 // It first attempts to generate request details using the HTTP response stored in the Request's status. If the generated
 // details are valid, the function returns them. If not, it falls back to using the cached response in the Request's status
 // and attempts to generate request details again. The function returns the generated request details or an error if the
-// generation process fails.
-func generateValidRequestDetails(cr *v1alpha1.Request, mapping *v1alpha1.Mapping) (requestgen.RequestDetails, error) {
-	requestDetails, _, ok := requestgen.GenerateRequestDetails(*mapping, cr.Spec.ForProvider, cr.Status.Response)
+// generation process fails. Values masked in the status are read unmasked from the Secret keeping them.
+func (c *external) generateValidRequestDetails(ctx context.Context, cr *v1alpha1.Request, mapping *v1alpha1.Mapping) (requestgen.RequestDetails, error) {
+	response, cache, err := statushandler.UnmaskedResponses(ctx, c.localKube, cr)
+	if err != nil {
+		return requestgen.RequestDetails{}, err
+	}
+
+	externalName := requestgen.WithExternalName(meta.GetExternalName(cr))
+
+	requestDetails, _, ok := requestgen.GenerateRequestDetails(*mapping, cr.Spec.ForProvider, response, externalName)
 	if requestgen.IsRequestValid(requestDetails) && ok {
 		return requestDetails, nil
 	}
 
-	requestDetails, err, _ := requestgen.GenerateRequestDetails(*mapping, cr.Spec.ForProvider, cr.Status.Cache.Response)
+	requestDetails, err, _ = requestgen.GenerateRequestDetails(*mapping, cr.Spec.ForProvider, cache, externalName)
 	if err != nil {
 		return requestgen.RequestDetails{}, err
 	}
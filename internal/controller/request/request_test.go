@@ -2,15 +2,24 @@ package request
 
 import (
 	"context"
+	"encoding/json"
+	"net/http"
 	"testing"
+	"time"
 
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
 	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/controller/request/statushandler"
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
 	"github.com/google/go-cmp/cmp"
@@ -18,7 +27,9 @@ import (
 )
 
 var (
-	errBoom = errors.New("boom")
+	errBoom        = errors.New("boom")
+	errRateLimited = &httpClient.RateLimitedError{Host: "api.example.com", Err: context.DeadlineExceeded, RetryAfter: 5 * time.Second}
+	errCircuitOpen = &httpClient.CircuitOpenError{Host: "api.example.com", Until: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
 )
 
 const (
@@ -108,7 +119,8 @@ func Test_httpExternal_Create(t *testing.T) {
 		mg        resource.Managed
 	}
 	type want struct {
-		err error
+		connectionDetails managed.ConnectionDetails
+		err               error
 	}
 
 	cases := map[string]struct {
@@ -140,6 +152,23 @@ func Test_httpExternal_Create(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToSendHttpRequest),
 			},
 		},
+		"RateLimited": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{}, errRateLimited
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					MockGet:          test.NewMockGetFn(nil),
+				},
+				mg: httpRequest(),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"Success": {
 			args: args{
 				http: &MockHttpClient{
@@ -158,6 +187,33 @@ func Test_httpExternal_Create(t *testing.T) {
 				err: nil,
 			},
 		},
+		"ConnectionDetails": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{
+							HttpResponse: httpClient.HttpResponse{StatusCode: 201, Body: `{"id":"123","password":"s3cr3t"}`},
+						}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					MockGet:          test.NewMockGetFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.ConnectionDetails = []apisv1alpha1.ConnectionDetail{
+						{Key: "id", Expression: ".body.id"},
+						{Key: "password", Expression: ".body.password"},
+					}
+				}),
+			},
+			want: want{
+				connectionDetails: managed.ConnectionDetails{
+					"id":       []byte("123"),
+					"password": []byte("s3cr3t"),
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		tc := tc // Create local copies of loop variables
@@ -168,10 +224,14 @@ func Test_httpExternal_Create(t *testing.T) {
 				logger:    logging.NewNopLogger(),
 				http:      tc.args.http,
 			}
-			_, gotErr := e.Create(context.Background(), tc.args.mg)
+			got, gotErr := e.Create(context.Background(), tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("e.Create(...): -want error, +got error: %s", diff)
 			}
+
+			if diff := cmp.Diff(tc.want.connectionDetails, got.ConnectionDetails); diff != "" {
+				t.Fatalf("e.Create(...): -want connection details, +got connection details: %s", diff)
+			}
 		})
 	}
 }
@@ -183,7 +243,8 @@ func Test_httpExternal_Update(t *testing.T) {
 		mg        resource.Managed
 	}
 	type want struct {
-		err error
+		err           error
+		circuitReason xpv1.ConditionReason
 	}
 
 	cases := map[string]struct {
@@ -215,6 +276,24 @@ func Test_httpExternal_Update(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToSendHttpRequest),
 			},
 		},
+		"CircuitOpen": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{}, errCircuitOpen
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(errBoom),
+					MockGet:          test.NewMockGetFn(nil),
+				},
+				mg: httpRequest(),
+			},
+			want: want{
+				err:           errors.Wrap(errCircuitOpen, errFailedToSendHttpRequest),
+				circuitReason: apisv1alpha1.ReasonCircuitOpen,
+			},
+		},
 		"Success": {
 			args: args{
 				http: &MockHttpClient{
@@ -233,6 +312,33 @@ func Test_httpExternal_Update(t *testing.T) {
 				err: nil,
 			},
 		},
+		"PatchMappingPreferred": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						if method != http.MethodPatch {
+							return httpClient.HttpDetails{}, errBoom
+						}
+						return httpClient.HttpDetails{}, nil
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					MockCreate:       test.NewMockCreateFn(nil),
+					MockGet:          test.NewMockGetFn(nil),
+				},
+				mg: httpRequest(func(r *v1alpha1.Request) {
+					r.Spec.ForProvider.Mappings = append([]v1alpha1.Mapping{{
+						Method: http.MethodPatch,
+						Body:   testPutMapping.Body,
+						URL:    testPutMapping.URL,
+					}}, r.Spec.ForProvider.Mappings...)
+				}),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 	}
 	for name, tc := range cases {
 		tc := tc // Create local copies of loop variables
@@ -247,6 +353,12 @@ func Test_httpExternal_Update(t *testing.T) {
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("e.Update(...): -want error, +got error: %s", diff)
 			}
+
+			if cr, ok := tc.args.mg.(*v1alpha1.Request); ok {
+				if diff := cmp.Diff(tc.want.circuitReason, cr.Status.GetCondition(apisv1alpha1.TypeCircuitOpen).Reason); diff != "" {
+					t.Fatalf("e.Update(...): -want circuit reason, +got circuit reason: %s", diff)
+				}
+			}
 		})
 	}
 }
@@ -290,6 +402,23 @@ func Test_httpExternal_Delete(t *testing.T) {
 				err: errors.Wrap(errBoom, errFailedToSendHttpRequest),
 			},
 		},
+		"AsyncOperationPending": {
+			args: args{
+				http: &MockHttpClient{
+					MockSendRequest: func(ctx context.Context, method string, url string, body string, headers map[string][]string, skipTLSVerify bool) (resp httpClient.HttpDetails, err error) {
+						return httpClient.HttpDetails{}, errBoom
+					},
+				},
+				localKube: &test.MockClient{
+					MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+					MockGet:          test.NewMockGetFn(nil),
+				},
+				mg: httpRequest(waitingFor(http.MethodPost, time.Now())),
+			},
+			want: want{
+				err: nil,
+			},
+		},
 		"Success": {
 			args: args{
 				http: &MockHttpClient{
@@ -325,3 +454,86 @@ func Test_httpExternal_Delete(t *testing.T) {
 		})
 	}
 }
+
+var testUnmaskedSecretRef = xpv1.SecretReference{Namespace: "crossplane-system", Name: "request-unmasked-uid"}
+
+// unmaskedSecret returns a Get filling the Secret keeping the unmasked
+// response, whose body is body.
+func unmaskedSecret(body string) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		response, _ := json.Marshal(v1alpha1.Response{StatusCode: 200, Body: body})
+		obj.(*corev1.Secret).Data = map[string][]byte{"response": response, "cache": []byte("{}")}
+		return nil
+	}
+}
+
+func Test_generateValidRequestDetails(t *testing.T) {
+	type args struct {
+		localKube client.Client
+		body      string
+		masked    *v1alpha1.Masked
+	}
+	type want struct {
+		url string
+		err error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NotMasked": {
+			args: args{
+				body: `{"id":"123"}`,
+			},
+			want: want{
+				url: "https://api.example.com/users/123",
+			},
+		},
+		"AsterisksNotMasked": {
+			args: args{
+				body: `{"id":"***"}`,
+			},
+			want: want{
+				url: "https://api.example.com/users/***",
+			},
+		},
+		"MaskedValueRestored": {
+			args: args{
+				localKube: &test.MockClient{MockGet: unmaskedSecret(`{"id":"123"}`)},
+				body:      `{"id":"***"}`,
+				masked:    &v1alpha1.Masked{Response: []string{"body"}, SecretRef: testUnmaskedSecretRef},
+			},
+			want: want{
+				url: "https://api.example.com/users/123",
+			},
+		},
+		"MaskedValuesLost": {
+			args: args{
+				localKube: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, testUnmaskedSecretRef.Name))},
+				body:      `{"id":"***"}`,
+				masked:    &v1alpha1.Masked{Response: []string{"body"}, SecretRef: testUnmaskedSecretRef},
+			},
+			want: want{
+				err: errors.New(statushandler.ErrMaskedValuesLost),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := httpRequest(func(r *v1alpha1.Request) {
+				r.Status.Response.Body = tc.args.body
+				r.Status.Masked = tc.args.masked
+			})
+
+			e := &external{localKube: tc.args.localKube}
+			got, err := e.generateValidRequestDetails(context.Background(), cr, &testPutMapping)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("generateValidRequestDetails(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.url, got.Url); diff != "" {
+				t.Fatalf("generateValidRequestDetails(...): -want URL, +got URL: %s", diff)
+			}
+		})
+	}
+}
@@ -20,9 +20,26 @@ type RequestDetails struct {
 	Headers map[string][]string
 }
 
+// An Option adds values to the object the expressions of a mapping are
+// evaluated against.
+type Option func(jqObject map[string]interface{})
+
+// WithExternalName makes name, the external name of the Request, available
+// as .externalName.
+func WithExternalName(name string) Option {
+	return func(jqObject map[string]interface{}) {
+		if name != "" {
+			jqObject["externalName"] = name
+		}
+	}
+}
+
 // GenerateRequestDetails generates request details.
-func GenerateRequestDetails(methodMapping v1alpha1.Mapping, forProvider v1alpha1.RequestParameters, response v1alpha1.Response) (RequestDetails, error, bool) {
+func GenerateRequestDetails(methodMapping v1alpha1.Mapping, forProvider v1alpha1.RequestParameters, response v1alpha1.Response, opts ...Option) (RequestDetails, error, bool) {
 	jqObject := generateRequestObject(forProvider, response)
+	for _, opt := range opts {
+		opt(jqObject)
+	}
 	url, err := generateURL(methodMapping.URL, jqObject)
 	if err != nil {
 		return RequestDetails{}, err, false
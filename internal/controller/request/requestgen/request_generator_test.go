@@ -66,6 +66,7 @@ func Test_GenerateRequestDetails(t *testing.T) {
 		methodMapping v1alpha1.Mapping
 		forProvider   v1alpha1.RequestParameters
 		response      v1alpha1.Response
+		opts          []Option
 		logger        logging.Logger
 	}
 	type want struct {
@@ -94,6 +95,26 @@ func Test_GenerateRequestDetails(t *testing.T) {
 				ok:  true,
 			},
 		},
+		"SuccessExternalName": {
+			args: args{
+				methodMapping: v1alpha1.Mapping{
+					Method: "GET",
+					URL:    "(.payload.baseUrl + \"/\" + (.response.body.id // .externalName))",
+				},
+				forProvider: testForProvider,
+				response:    v1alpha1.Response{},
+				opts:        []Option{WithExternalName("123")},
+				logger:      logging.NewNopLogger(),
+			},
+			want: want{
+				requestDetails: RequestDetails{
+					Url:     "https://api.example.com/users/123",
+					Headers: map[string][]string{},
+				},
+				err: nil,
+				ok:  true,
+			},
+		},
 		"SuccessPut": {
 			args: args{
 				methodMapping: testPutMapping,
@@ -158,7 +179,7 @@ func Test_GenerateRequestDetails(t *testing.T) {
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			got, gotErr, ok := GenerateRequestDetails(tc.args.methodMapping, tc.args.forProvider, tc.args.response)
+			got, gotErr, ok := GenerateRequestDetails(tc.args.methodMapping, tc.args.forProvider, tc.args.response, tc.args.opts...)
 			if diff := cmp.Diff(tc.want.err, gotErr, test.EquateErrors()); diff != "" {
 				t.Fatalf("GenerateRequestDetails(...): -want error, +got error: %s", diff)
 			}
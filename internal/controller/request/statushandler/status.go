@@ -11,6 +11,7 @@ import (
 	"github.com/crossplane-contrib/provider-http/internal/controller/request/responseconverter"
 	"github.com/crossplane-contrib/provider-http/internal/utils"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -30,6 +31,9 @@ type requestStatusHandler struct {
 	resource      *utils.RequestResource
 	responseError error
 	forProvider   v1alpha1.RequestParameters
+	cacheSet      bool
+	request       *v1alpha1.Request
+	masking       Masking
 }
 
 // SetRequestStatus updates the current Request's status to reflect the details of the last HTTP request that occurred.
@@ -58,10 +62,70 @@ func (r *requestStatusHandler) SetRequestStatus() error {
 		r.appendExtraSetters(r.forProvider, &basicSetters)
 	}
 
-	if settingError := utils.SetRequestResourceStatus(*r.resource, basicSetters...); settingError != nil {
-		return errors.Wrap(settingError, utils.ErrFailedToSetStatus)
+	return r.setStatus(basicSetters)
+}
+
+// maskedSetter keeps the values the status setters mask in a Secret, and
+// returns the setter recording the masked fields in the status.
+func (r *requestStatusHandler) maskedSetter() (utils.SetRequestStatusFunc, error) {
+	cr := r.request
+	if r.masking.Masker == nil && cr.Status.Masked == nil {
+		return func() {}, nil
+	}
+
+	response, cache, err := UnmaskedResponses(r.resource.RequestContext, r.resource.LocalClient, cr)
+	if err != nil {
+		// The values masked in the status are lost, keep what is left.
+		response, cache = cr.Status.Response, cr.Status.Cache.Response
+	}
+
+	masked := r.resource.HttpResponse
+	if r.masking.Masker != nil {
+		masked = r.masking.Masker.Response(masked)
+	}
+
+	maskedResponse := writtenResponse(cr.Status.Response, masked)
+	response = writtenResponse(response, r.resource.HttpResponse)
+	maskedCache := cr.Status.Cache.Response
+	if r.cacheSet {
+		cache = responseconverter.HttpResponseToV1alpha1Response(r.resource.HttpResponse)
+		maskedCache = responseconverter.HttpResponseToV1alpha1Response(masked)
+	}
+
+	fields := &v1alpha1.Masked{
+		Response:  maskedFields(response, maskedResponse),
+		Cache:     maskedFields(cache, maskedCache),
+		SecretRef: unmaskedSecretRef(cr, r.masking.Namespace),
 	}
+	if len(fields.Response) == 0 && len(fields.Cache) == 0 {
+		return func() { cr.Status.Masked = nil }, nil
+	}
+
+	if cr.Status.Masked != nil {
+		fields.SecretRef = cr.Status.Masked.SecretRef
+	}
+	if err := keepUnmasked(r.resource.RequestContext, r.resource.LocalClient, cr, fields.SecretRef, response, cache); err != nil {
+		return nil, err
+	}
+	return func() { cr.Status.Masked = fields }, nil
+}
 
+// setStatus sets the status of the resource with the given setters, and keeps
+// the values they mask.
+func (r *requestStatusHandler) setStatus(setters []utils.SetRequestStatusFunc) error {
+	previous := r.request.Status.Masked
+	maskedSetter, err := r.maskedSetter()
+	if err != nil {
+		return errors.Wrap(err, utils.ErrFailedToSetStatus)
+	}
+
+	if err := utils.SetRequestResourceStatus(*r.resource, append(setters, maskedSetter)...); err != nil {
+		return errors.Wrap(err, utils.ErrFailedToSetStatus)
+	}
+
+	if previous != nil && r.request.Status.Masked == nil {
+		return errors.Wrap(forgetUnmasked(r.resource.RequestContext, r.resource.LocalClient, previous.SecretRef), utils.ErrFailedToSetStatus)
+	}
 	return nil
 }
 
@@ -76,8 +140,8 @@ func (r *requestStatusHandler) setErrorAndReturn(err error) error {
 func (r *requestStatusHandler) incrementFailuresAndReturn(combinedSetters []utils.SetRequestStatusFunc) error {
 	combinedSetters = append(combinedSetters, r.resource.SetError(nil)) // should increment failures counter
 
-	if settingError := utils.SetRequestResourceStatus(*r.resource, combinedSetters...); settingError != nil {
-		return errors.Wrap(settingError, utils.ErrFailedToSetStatus)
+	if settingError := r.setStatus(combinedSetters); settingError != nil {
+		return settingError
 	}
 
 	return errors.Errorf(utils.ErrStatusCode, r.resource.HttpRequest.Method, strconv.Itoa(r.resource.HttpResponse.StatusCode))
@@ -90,6 +154,7 @@ func (r *requestStatusHandler) appendExtraSetters(forProvider v1alpha1.RequestPa
 
 	if r.shouldSetCache(forProvider) {
 		*combinedSetters = append(*combinedSetters, r.resource.SetCache())
+		r.cacheSet = true
 	}
 }
 
@@ -99,7 +164,7 @@ func (r *requestStatusHandler) appendExtraSetters(forProvider v1alpha1.RequestPa
 func (r *requestStatusHandler) shouldSetCache(forProvider v1alpha1.RequestParameters) bool {
 	for _, mapping := range forProvider.Mappings {
 		response := responseconverter.HttpResponseToV1alpha1Response(r.resource.HttpResponse)
-		requestDetails, _, ok := requestgen.GenerateRequestDetails(mapping, forProvider, response)
+		requestDetails, _, ok := requestgen.GenerateRequestDetails(mapping, forProvider, response, requestgen.WithExternalName(meta.GetExternalName(r.resource.Resource)))
 		if !(requestgen.IsRequestValid(requestDetails) && ok) {
 			return false
 		}
@@ -116,8 +181,9 @@ func (r *requestStatusHandler) ResetFailures() {
 	*r.extraSetters = append(*r.extraSetters, r.resource.ResetFailures())
 }
 
-// NewClient returns a new Request statusHandler
-func NewStatusHandler(ctx context.Context, cr *v1alpha1.Request, requestDetails httpClient.HttpDetails, err error, localKube client.Client, logger logging.Logger) (RequestStatusHandler, error) {
+// NewClient returns a new Request statusHandler. The values hidden by the
+// masking, if any, are replaced in the status and kept in a Secret.
+func NewStatusHandler(ctx context.Context, cr *v1alpha1.Request, requestDetails httpClient.HttpDetails, err error, localKube client.Client, logger logging.Logger, masking Masking) (RequestStatusHandler, error) {
 	// Get the latest version of the resource before updating
 	if err := localKube.Get(ctx, types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, cr); err != nil {
 		return nil, errors.Wrap(err, "failed to get the latest version of the resource")
@@ -132,9 +198,12 @@ func NewStatusHandler(ctx context.Context, cr *v1alpha1.Request, requestDetails
 			HttpRequest:    requestDetails.HttpRequest,
 			RequestContext: ctx,
 			LocalClient:    localKube,
+			Masker:         masking.Masker,
 		},
 		responseError: err,
 		forProvider:   cr.Spec.ForProvider,
+		request:       cr,
+		masking:       masking,
 	}
 
 	return requestStatusHandler, nil
@@ -0,0 +1,180 @@
+package statushandler
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+const (
+	errGetUnmasked    = "cannot get the Secret keeping the unmasked status"
+	errParseUnmasked  = "cannot parse the unmasked status"
+	errKeepUnmasked   = "cannot keep the unmasked status"
+	errDeleteUnmasked = "cannot delete the Secret keeping the unmasked status"
+
+	unmaskedSecretPrefix = "request-unmasked-"
+	unmaskedResponseKey  = "response"
+	unmaskedCacheKey     = "cache"
+
+	maskedBody          = "body"
+	maskedHeadersPrefix = "headers."
+)
+
+// ErrMaskedValuesLost is returned when the values masked in the status of a
+// Request are read after the Secret keeping them was deleted.
+const ErrMaskedValuesLost = "the Secret keeping the values masked in the status was deleted; they are kept again once the resource is observed"
+
+// Masking hides values of the status of Requests. Their unmasked values are
+// kept in Secrets of Namespace, so the mappings can still reference them.
+type Masking struct {
+	// Masker replaces the masked values, nil if none is.
+	Masker *httpClient.Redactor
+
+	// Namespace of the Secrets keeping the unmasked values.
+	Namespace string
+}
+
+// UnmaskedResponses returns the response and the cached response of cr,
+// rebuilt from its status and the Secret keeping the values listed in
+// status.masked.
+func UnmaskedResponses(ctx context.Context, kube client.Reader, cr *v1alpha1.Request) (response, cache v1alpha1.Response, err error) {
+	response, cache = cr.Status.Response, cr.Status.Cache.Response
+
+	masked := cr.Status.Masked
+	if masked == nil || (len(masked.Response) == 0 && len(masked.Cache) == 0) {
+		return response, cache, nil
+	}
+
+	unmaskedResponse, unmaskedCache, err := getUnmasked(ctx, kube, masked.SecretRef)
+	if err != nil {
+		return v1alpha1.Response{}, v1alpha1.Response{}, err
+	}
+
+	return unmask(response, unmaskedResponse, masked.Response), unmask(cache, unmaskedCache, masked.Cache), nil
+}
+
+func getUnmasked(ctx context.Context, kube client.Reader, ref xpv1.SecretReference) (response, cache v1alpha1.Response, err error) {
+	s := &corev1.Secret{}
+	if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		if kerrors.IsNotFound(err) {
+			return response, cache, errors.New(ErrMaskedValuesLost)
+		}
+		return response, cache, errors.Wrap(err, errGetUnmasked)
+	}
+
+	if err := json.Unmarshal(s.Data[unmaskedResponseKey], &response); err != nil {
+		return response, cache, errors.Wrap(err, errParseUnmasked)
+	}
+	if err := json.Unmarshal(s.Data[unmaskedCacheKey], &cache); err != nil {
+		return response, cache, errors.Wrap(err, errParseUnmasked)
+	}
+
+	return response, cache, nil
+}
+
+// unmask returns status with the given masked fields read from unmasked.
+func unmask(status, unmasked v1alpha1.Response, fields []string) v1alpha1.Response {
+	for _, f := range fields {
+		if f == maskedBody {
+			status.Body = unmasked.Body
+			continue
+		}
+
+		name := strings.TrimPrefix(f, maskedHeadersPrefix)
+		headers := make(map[string][]string, len(status.Headers))
+		for k, v := range status.Headers {
+			headers[k] = v
+		}
+		headers[name] = unmasked.Headers[name]
+		status.Headers = headers
+	}
+	return status
+}
+
+// maskedFields lists the fields of masked that differ from unmasked.
+func maskedFields(unmasked, masked v1alpha1.Response) []string {
+	var fields []string
+	if unmasked.Body != masked.Body {
+		fields = append(fields, maskedBody)
+	}
+	for name, values := range unmasked.Headers {
+		if !reflect.DeepEqual(values, masked.Headers[name]) {
+			fields = append(fields, maskedHeadersPrefix+name)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// writtenResponse returns status once the response is written to it, as the
+// status setters do.
+func writtenResponse(status v1alpha1.Response, response httpClient.HttpResponse) v1alpha1.Response {
+	if response.StatusCode != 0 {
+		status.StatusCode = response.StatusCode
+	}
+	if response.Headers != nil {
+		status.Headers = response.Headers
+	}
+	if response.Body != "" {
+		status.Body = response.Body
+	}
+	return status
+}
+
+// unmaskedSecretRef returns the Secret keeping the unmasked status of cr.
+func unmaskedSecretRef(cr *v1alpha1.Request, namespace string) xpv1.SecretReference {
+	return xpv1.SecretReference{Namespace: namespace, Name: unmaskedSecretPrefix + string(cr.GetUID())}
+}
+
+// keepUnmasked keeps the unmasked response and cache of cr in the Secret ref,
+// owned by cr so it is deleted along with it.
+func keepUnmasked(ctx context.Context, kube client.Client, cr *v1alpha1.Request, ref xpv1.SecretReference, response, cache v1alpha1.Response) error {
+	data := map[string][]byte{}
+	var err error
+	if data[unmaskedResponseKey], err = json.Marshal(response); err != nil {
+		return errors.Wrap(err, errKeepUnmasked)
+	}
+	if data[unmaskedCacheKey], err = json.Marshal(cache); err != nil {
+		return errors.Wrap(err, errKeepUnmasked)
+	}
+
+	s := &corev1.Secret{}
+	err = kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s)
+	switch {
+	case kerrors.IsNotFound(err):
+		s = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       ref.Namespace,
+				Name:            ref.Name,
+				OwnerReferences: []metav1.OwnerReference{meta.AsOwner(meta.TypedReferenceTo(cr, v1alpha1.RequestGroupVersionKind))},
+			},
+			Data: data,
+		}
+		err = kube.Create(ctx, s)
+	case err == nil && !reflect.DeepEqual(s.Data, data):
+		s.Data = data
+		err = kube.Update(ctx, s)
+	}
+	return errors.Wrap(err, errKeepUnmasked)
+}
+
+// forgetUnmasked deletes the Secret ref once no value of the status is masked.
+func forgetUnmasked(ctx context.Context, kube client.Client, ref xpv1.SecretReference) error {
+	s := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: ref.Namespace, Name: ref.Name}}
+	return errors.Wrap(resource.IgnoreNotFound(kube.Delete(ctx, s)), errDeleteUnmasked)
+}
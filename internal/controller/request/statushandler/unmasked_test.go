@@ -0,0 +1,239 @@
+package statushandler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+)
+
+// secretClient returns a client keeping the Secrets it writes in secrets.
+func secretClient(secrets map[string]*corev1.Secret) *test.MockClient {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			s, ok := obj.(*corev1.Secret)
+			if !ok {
+				return nil
+			}
+			kept, ok := secrets[key.Name]
+			if !ok {
+				return kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, key.Name)
+			}
+			kept.DeepCopyInto(s)
+			return nil
+		},
+		MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			secrets[obj.GetName()] = obj.(*corev1.Secret).DeepCopy()
+			return nil
+		},
+		MockUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+			secrets[obj.GetName()] = obj.(*corev1.Secret).DeepCopy()
+			return nil
+		},
+		MockDelete: func(_ context.Context, obj client.Object, _ ...client.DeleteOption) error {
+			delete(secrets, obj.GetName())
+			return nil
+		},
+		MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+	}
+}
+
+func Test_UnmaskedResponses(t *testing.T) {
+	masker, err := httpClient.NewMasker(httpClient.RedactionConfig{
+		Headers:   []string{"X-Token"},
+		BodyPaths: []string{".password"},
+	})
+	if err != nil {
+		t.Fatalf("NewMasker(...): unexpected error: %s", err)
+	}
+
+	response := httpClient.HttpResponse{
+		StatusCode: 200,
+		Body:       `{"id":"123","password":"s3cr3t"}`,
+		Headers:    map[string][]string{"X-Token": {"t0k3n"}, "Content-Type": {"application/json"}},
+	}
+	unmasked := v1alpha1.Response{StatusCode: response.StatusCode, Body: response.Body, Headers: response.Headers}
+	masked := v1alpha1.Response{
+		StatusCode: 200,
+		Body:       `{"id":"123","password":"***"}`,
+		Headers:    map[string][]string{"X-Token": {"***"}, "Content-Type": {"application/json"}},
+	}
+
+	cr := &v1alpha1.Request{
+		ObjectMeta: metav1.ObjectMeta{Name: "masked", UID: "masked-request"},
+		Spec:       v1alpha1.RequestSpec{ForProvider: testForProvider},
+	}
+	secrets := map[string]*corev1.Secret{}
+	localKube := secretClient(secrets)
+	masking := Masking{Masker: masker, Namespace: "crossplane-system"}
+
+	r, err := NewStatusHandler(context.Background(), cr, httpClient.HttpDetails{
+		HttpResponse: response,
+		HttpRequest: httpClient.HttpRequest{
+			Method:  testMethod,
+			URL:     "https://api.example.com/users",
+			Body:    `{"password":"s3cr3t"}`,
+			Headers: map[string][]string{"X-Token": {"t0k3n"}},
+		},
+	}, nil, localKube, logging.NewNopLogger(), masking)
+	if err != nil {
+		t.Fatalf("NewStatusHandler(...): unexpected error: %s", err)
+	}
+
+	if err := r.SetRequestStatus(); err != nil {
+		t.Fatalf("SetRequestStatus(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(masked, cr.Status.Response); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want Status.Response, +got Status.Response: %s", diff)
+	}
+
+	if diff := cmp.Diff(masked, cr.Status.Cache.Response); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want Status.Cache.Response, +got Status.Cache.Response: %s", diff)
+	}
+
+	wantDetails := v1alpha1.RequestDetails{
+		Method:  testMethod,
+		URL:     "https://api.example.com/users",
+		Body:    `{"password":"***"}`,
+		Headers: map[string][]string{"X-Token": {"***"}},
+	}
+	if diff := cmp.Diff(wantDetails, cr.Status.RequestDetails); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want Status.RequestDetails, +got Status.RequestDetails: %s", diff)
+	}
+
+	wantMasked := &v1alpha1.Masked{
+		Response:  []string{"body", "headers.X-Token"},
+		Cache:     []string{"body", "headers.X-Token"},
+		SecretRef: xpv1.SecretReference{Namespace: "crossplane-system", Name: "request-unmasked-masked-request"},
+	}
+	if diff := cmp.Diff(wantMasked, cr.Status.Masked); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want Status.Masked, +got Status.Masked: %s", diff)
+	}
+	if diff := cmp.Diff(1, len(secrets)); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want kept Secrets, +got kept Secrets: %s", diff)
+	}
+
+	gotResponse, gotCache, err := UnmaskedResponses(context.Background(), localKube, cr)
+	if err != nil {
+		t.Fatalf("UnmaskedResponses(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(unmasked, gotResponse); diff != "" {
+		t.Fatalf("UnmaskedResponses(...): -want response, +got response: %s", diff)
+	}
+	if diff := cmp.Diff(unmasked, gotCache); diff != "" {
+		t.Fatalf("UnmaskedResponses(...): -want cache, +got cache: %s", diff)
+	}
+
+	// Once no value is masked, the Secret is deleted.
+	r, err = NewStatusHandler(context.Background(), cr, httpClient.HttpDetails{
+		HttpResponse: httpClient.HttpResponse{StatusCode: 200, Body: `{"id":"123"}`, Headers: map[string][]string{}},
+		HttpRequest:  httpClient.HttpRequest{Method: testMethod, URL: "https://api.example.com/users"},
+	}, nil, localKube, logging.NewNopLogger(), masking)
+	if err != nil {
+		t.Fatalf("NewStatusHandler(...): unexpected error: %s", err)
+	}
+	if err := r.SetRequestStatus(); err != nil {
+		t.Fatalf("SetRequestStatus(...): unexpected error: %s", err)
+	}
+	if cr.Status.Masked != nil {
+		t.Fatalf("SetRequestStatus(...): want no Status.Masked, got %v", cr.Status.Masked)
+	}
+	if diff := cmp.Diff(0, len(secrets)); diff != "" {
+		t.Fatalf("SetRequestStatus(...): -want kept Secrets, +got kept Secrets: %s", diff)
+	}
+}
+
+func Test_UnmaskedResponsesFromSecret(t *testing.T) {
+	ref := xpv1.SecretReference{Namespace: "crossplane-system", Name: "request-unmasked-uid"}
+	status := v1alpha1.RequestStatus{
+		Response: v1alpha1.Response{
+			StatusCode: 200,
+			Body:       `{"password":"***"}`,
+			Headers:    map[string][]string{"X-Token": {"***"}, "X-Literal": {"***"}},
+		},
+		Cache: v1alpha1.Cache{Response: v1alpha1.Response{StatusCode: 200, Body: `{"password":"***"}`}},
+	}
+	kept := map[string]*corev1.Secret{
+		ref.Name: {Data: map[string][]byte{
+			unmaskedResponseKey: []byte(`{"statusCode":200,"body":"{\"password\":\"s3cr3t\"}","headers":{"X-Token":["t0k3n"],"X-Literal":["***"]}}`),
+			unmaskedCacheKey:    []byte(`{"statusCode":200,"body":"{\"password\":\"old\"}"}`),
+		}},
+	}
+
+	type args struct {
+		secrets map[string]*corev1.Secret
+		masked  *v1alpha1.Masked
+	}
+	type want struct {
+		response v1alpha1.Response
+		cache    v1alpha1.Response
+		err      error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoneMasked": {
+			args: args{
+				secrets: map[string]*corev1.Secret{},
+			},
+			want: want{
+				response: status.Response,
+				cache:    status.Cache.Response,
+			},
+		},
+		"MaskedFields": {
+			args: args{
+				secrets: kept,
+				masked:  &v1alpha1.Masked{Response: []string{"body", "headers.X-Token"}, Cache: []string{"body"}, SecretRef: ref},
+			},
+			want: want{
+				response: v1alpha1.Response{
+					StatusCode: 200,
+					Body:       `{"password":"s3cr3t"}`,
+					Headers:    map[string][]string{"X-Token": {"t0k3n"}, "X-Literal": {"***"}},
+				},
+				cache: v1alpha1.Response{StatusCode: 200, Body: `{"password":"old"}`},
+			},
+		},
+		"SecretLost": {
+			args: args{
+				secrets: map[string]*corev1.Secret{},
+				masked:  &v1alpha1.Masked{Response: []string{"body"}, SecretRef: ref},
+			},
+			want: want{
+				err: errors.New(ErrMaskedValuesLost),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.Request{Status: *status.DeepCopy()}
+			cr.Status.Masked = tc.args.masked
+
+			gotResponse, gotCache, err := UnmaskedResponses(context.Background(), secretClient(tc.args.secrets), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("UnmaskedResponses(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.response, gotResponse); diff != "" {
+				t.Fatalf("UnmaskedResponses(...): -want response, +got response: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.cache, gotCache); diff != "" {
+				t.Fatalf("UnmaskedResponses(...): -want cache, +got cache: %s", diff)
+			}
+		})
+	}
+}
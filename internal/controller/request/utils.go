@@ -1,6 +1,10 @@
 package request
 
 import (
+	"net/http"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
 	"github.com/crossplane-contrib/provider-http/apis/request/v1alpha1"
 )
 
@@ -12,3 +16,18 @@ func getMappingByMethod(requestParams *v1alpha1.RequestParameters, method string
 	}
 	return nil, false
 }
+
+// updateMethod returns the method of the mapping updating the resource: PATCH
+// if there is a PATCH mapping, PUT otherwise.
+func updateMethod(requestParams *v1alpha1.RequestParameters) string {
+	if _, ok := getMappingByMethod(requestParams, http.MethodPatch); ok {
+		return http.MethodPatch
+	}
+	return http.MethodPut
+}
+
+// isObserveOnly tells whether the management policy of cr only observes its
+// resource, so it is never created, updated nor deleted.
+func isObserveOnly(cr *v1alpha1.Request) bool {
+	return cr.GetManagementPolicy() == xpv1.ManagementObserveOnly
+}
@@ -41,6 +41,11 @@ func runJQQuery(jqQuery string, obj interface{}) (interface{}, error) {
 	return queryRes, nil
 }
 
+// ParseValue returns the first result of jqQuery on obj, whatever its type.
+func ParseValue(jqQuery string, obj interface{}) (interface{}, error) {
+	return runJQQuery(jqQuery, obj)
+}
+
 func ParseString(jqQuery string, obj interface{}) (string, error) {
 	queryRes, err := runJQQuery(jqQuery, obj)
 	if err != nil {
@@ -0,0 +1,119 @@
+package json
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Diff lists the paths, written as jq paths, through which an observed state
+// differs from a desired state.
+type Diff struct {
+	// Added are the paths of the desired state missing from the observed
+	// state.
+	Added []string
+
+	// Removed are the paths of the observed state the desired state doesn't
+	// have. Top level fields are never removed, as Contains ignores them.
+	Removed []string
+
+	// Changed are the paths whose values differ.
+	Changed []string
+}
+
+// Empty tells whether the states don't differ.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// ContainsDiff returns why container doesn't contain containee, following the
+// semantics of Contains: the top level fields of containee must be in
+// container with equal values. The Diff is empty if it does.
+func ContainsDiff(container, containee map[string]interface{}) Diff {
+	d := &Diff{}
+	for _, key := range sortedKeys(containee) {
+		path := fieldPath("", key)
+		containerValue, exists := container[key]
+		if !exists {
+			d.Added = append(d.Added, path)
+			continue
+		}
+		d.values(path, containee[key], containerValue)
+	}
+	return *d
+}
+
+// values adds the differences between the desired and the observed value at
+// path.
+func (d *Diff) values(path string, desired, observed interface{}) {
+	if deepEqual(desired, observed) {
+		return
+	}
+
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		observedValue, ok := observed.(map[string]interface{})
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(desiredValue) {
+			if _, exists := observedValue[key]; !exists {
+				d.Added = append(d.Added, fieldPath(path, key))
+				continue
+			}
+			d.values(fieldPath(path, key), desiredValue[key], observedValue[key])
+		}
+		for _, key := range sortedKeys(observedValue) {
+			if _, exists := desiredValue[key]; !exists {
+				d.Removed = append(d.Removed, fieldPath(path, key))
+			}
+		}
+		return
+	case []interface{}:
+		observedValue, ok := observed.([]interface{})
+		if !ok {
+			break
+		}
+		for i := range desiredValue {
+			if i >= len(observedValue) {
+				d.Added = append(d.Added, indexPath(path, i))
+				continue
+			}
+			d.values(indexPath(path, i), desiredValue[i], observedValue[i])
+		}
+		for i := len(desiredValue); i < len(observedValue); i++ {
+			d.Removed = append(d.Removed, indexPath(path, i))
+		}
+		return
+	}
+
+	d.Changed = append(d.Changed, path)
+}
+
+var identifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func fieldPath(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+	if path == "" {
+		path = "."
+	}
+	return fmt.Sprintf("%s[%q]", path, key)
+}
+
+func indexPath(path string, i int) string {
+	if path == "" {
+		path = "."
+	}
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
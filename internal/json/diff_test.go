@@ -0,0 +1,93 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_ContainsDiff(t *testing.T) {
+	type args struct {
+		container string
+		containee string
+	}
+	type want struct {
+		diff Diff
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"Contained": {
+			args: args{
+				container: `{"id":"123","username":"john_doe","settings":{"theme":"dark"}}`,
+				containee: `{"username":"john_doe","settings":{"theme":"dark"}}`,
+			},
+			want: want{},
+		},
+		"TopLevel": {
+			args: args{
+				container: `{"id":"123","username":"john_doe"}`,
+				containee: `{"username":"jane_doe","email":"jane@example.com"}`,
+			},
+			want: want{
+				diff: Diff{
+					Added:   []string{".email"},
+					Changed: []string{".username"},
+				},
+			},
+		},
+		"Nested": {
+			args: args{
+				container: `{"settings":{"theme":"light","updatedAt":"2024-01-01","tags":{"team":"a"}}}`,
+				containee: `{"settings":{"theme":"dark","language":"en","tags":{"team":"a"}}}`,
+			},
+			want: want{
+				diff: Diff{
+					Added:   []string{".settings.language"},
+					Removed: []string{".settings.updatedAt"},
+					Changed: []string{".settings.theme"},
+				},
+			},
+		},
+		"Arrays": {
+			args: args{
+				container: `{"roles":["admin","dev","ops"],"members":[{"name":"john"}]}`,
+				containee: `{"roles":["admin","qa"],"members":[{"name":"john"},{"name":"jane"}]}`,
+			},
+			want: want{
+				diff: Diff{
+					Added:   []string{".members[1]"},
+					Removed: []string{".roles[2]"},
+					Changed: []string{".roles[1]"},
+				},
+			},
+		},
+		"TypeChanged": {
+			args: args{
+				container: `{"size":"10","labels":["a"],"x-request-id":{"v":1}}`,
+				containee: `{"size":10,"labels":{"a":true},"x-request-id":{"v":2}}`,
+			},
+			want: want{
+				diff: Diff{
+					Changed: []string{".labels", ".size", `.["x-request-id"].v`},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			container := JsonStringToMap(tc.args.container)
+			containee := JsonStringToMap(tc.args.containee)
+
+			got := ContainsDiff(container, containee)
+			if diff := cmp.Diff(tc.want.diff, got); diff != "" {
+				t.Fatalf("ContainsDiff(...): -want diff, +got diff: %s", diff)
+			}
+
+			if diff := cmp.Diff(Contains(container, containee), got.Empty()); diff != "" {
+				t.Fatalf("ContainsDiff(...): -want Contains(...), +got Empty(): %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package json
+
+import (
+	"strings"
+
+	"github.com/itchyny/gojq"
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseNormalization = "cannot parse comparison rules"
+	errNormalize          = "cannot normalize JSON document"
+	errNormalizedNotMap   = "normalized JSON document is not an object"
+)
+
+// CompareOptions are the rules applied to both JSON documents before they are
+// compared, in order: Normalize, IgnorePaths, then ArraysAsSets.
+type CompareOptions struct {
+	// Normalize is a jq expression rewriting the documents, e.g.
+	// .size |= tonumber.
+	Normalize string
+
+	// IgnorePaths are jq paths of the values removed from the documents,
+	// e.g. .updatedAt or .items[].etag.
+	IgnorePaths []string
+
+	// ArraysAsSets are jq paths of arrays compared regardless of the order
+	// and the duplicates of their elements, e.g. .tags or .rules[].ports.
+	ArraysAsSets []string
+}
+
+// Normalizer applies CompareOptions to JSON documents.
+type Normalizer struct {
+	code *gojq.Code
+}
+
+// NewNormalizer returns a Normalizer applying opts. It returns nil if opts
+// holds no rule, and a nil Normalizer leaves documents unchanged.
+func NewNormalizer(opts CompareOptions) (*Normalizer, error) {
+	var stages []string
+	if opts.Normalize != "" {
+		stages = append(stages, "("+opts.Normalize+")")
+	}
+	if len(opts.IgnorePaths) > 0 {
+		stages = append(stages, "delpaths(["+tryPaths(opts.IgnorePaths)+"])")
+	}
+	if len(opts.ArraysAsSets) > 0 {
+		stages = append(stages, "reduce ("+tryPaths(opts.ArraysAsSets)+") as $p (.; "+
+			`if (getpath($p) | type) == "array" then setpath($p; getpath($p) | unique) else . end)`)
+	}
+
+	if len(stages) == 0 {
+		return nil, nil
+	}
+
+	query, err := gojq.Parse(strings.Join(stages, " | "))
+	if err != nil {
+		return nil, errors.Wrap(err, errParseNormalization)
+	}
+
+	code, err := gojq.Compile(query)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseNormalization)
+	}
+
+	return &Normalizer{code: code}, nil
+}
+
+// tryPaths returns a jq expression yielding the paths of exprs that apply to
+// the document, skipping the others rather than failing.
+func tryPaths(exprs []string) string {
+	paths := make([]string, len(exprs))
+	for i, e := range exprs {
+		paths[i] = "(try path(" + e + ") catch empty)"
+	}
+	return strings.Join(paths, ", ")
+}
+
+// Normalize returns a normalized copy of obj.
+func (n *Normalizer) Normalize(obj map[string]interface{}) (map[string]interface{}, error) {
+	if n == nil || obj == nil {
+		return obj, nil
+	}
+
+	// jq may share the values of its input with its result.
+	result, ok := n.code.Run(deepCopy(obj)).Next()
+	if !ok {
+		return nil, errors.New(errNormalize)
+	}
+	if err, isErr := result.(error); isErr {
+		return nil, errors.Wrap(err, errNormalize)
+	}
+
+	normalized, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(errNormalizedNotMap)
+	}
+
+	// jq may return numbers as integers, decoded JSON holds float64.
+	return deepCopy(normalized), nil
+}
+
+// deepCopy copies obj through its JSON encoding.
+func deepCopy(obj map[string]interface{}) map[string]interface{} {
+	copied, _ := StructToMap(obj)
+	return copied
+}
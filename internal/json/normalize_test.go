@@ -0,0 +1,133 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Normalizer(t *testing.T) {
+	type args struct {
+		opts      CompareOptions
+		container string
+		containee string
+	}
+	type want struct {
+		container string
+		contains  bool
+		diff      Diff
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoRules": {
+			args: args{
+				container: `{"name":"web","updatedAt":"2024-01-02","tags":["b","a"]}`,
+				containee: `{"name":"web","tags":["a","b"]}`,
+			},
+			want: want{
+				container: `{"name":"web","updatedAt":"2024-01-02","tags":["b","a"]}`,
+				contains:  false,
+				diff:      Diff{Changed: []string{".tags[0]", ".tags[1]"}},
+			},
+		},
+		"IgnoreNestedPaths": {
+			args: args{
+				opts: CompareOptions{
+					IgnorePaths: []string{".updatedAt", ".settings.updatedAt", ".items[].etag", ".missing.path", ".name.first"},
+				},
+				container: `{"name":"web","updatedAt":"2024-01-02","settings":{"theme":"dark","updatedAt":"2024-01-02"},"items":[{"id":1,"etag":"a"},{"id":2,"etag":"b"}]}`,
+				containee: `{"name":"web","settings":{"theme":"dark"},"items":[{"id":1},{"id":2}]}`,
+			},
+			want: want{
+				container: `{"name":"web","settings":{"theme":"dark"},"items":[{"id":1},{"id":2}]}`,
+				contains:  true,
+			},
+		},
+		"ArraysAsSets": {
+			args: args{
+				opts: CompareOptions{
+					ArraysAsSets: []string{".tags", ".rules[].ports", ".name"},
+				},
+				container: `{"name":"web","tags":["b","a","b"],"rules":[{"ports":[443,80]},{"ports":[22]}]}`,
+				containee: `{"name":"web","tags":["a","b"],"rules":[{"ports":[80,443]},{"ports":[22]}]}`,
+			},
+			want: want{
+				container: `{"name":"web","tags":["a","b"],"rules":[{"ports":[80,443]},{"ports":[22]}]}`,
+				contains:  true,
+			},
+		},
+		"ArraysAsSetsStillDiffer": {
+			args: args{
+				opts: CompareOptions{
+					ArraysAsSets: []string{".tags"},
+				},
+				container: `{"tags":["c","a"]}`,
+				containee: `{"tags":["b","a"]}`,
+			},
+			want: want{
+				container: `{"tags":["a","c"]}`,
+				contains:  false,
+				diff:      Diff{Changed: []string{".tags[1]"}},
+			},
+		},
+		"Normalize": {
+			args: args{
+				opts: CompareOptions{
+					Normalize:   `if .size then .size |= tonumber else . end | .labels |= (. // {} | with_entries(.key |= ascii_downcase))`,
+					IgnorePaths: []string{".id"},
+				},
+				container: `{"id":"123","size":"10","labels":{"Team":"a"}}`,
+				containee: `{"size":10,"labels":{"team":"a"}}`,
+			},
+			want: want{
+				container: `{"size":10,"labels":{"team":"a"}}`,
+				contains:  true,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			n, err := NewNormalizer(tc.args.opts)
+			if err != nil {
+				t.Fatalf("NewNormalizer(...): unexpected error: %s", err)
+			}
+
+			container, err := n.Normalize(JsonStringToMap(tc.args.container))
+			if err != nil {
+				t.Fatalf("Normalize(...): unexpected error: %s", err)
+			}
+			containee, err := n.Normalize(JsonStringToMap(tc.args.containee))
+			if err != nil {
+				t.Fatalf("Normalize(...): unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(JsonStringToMap(tc.want.container), container); diff != "" {
+				t.Fatalf("Normalize(...): -want container, +got container: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.contains, Contains(container, containee)); diff != "" {
+				t.Fatalf("Contains(...): -want result, +got result: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.diff, ContainsDiff(container, containee)); diff != "" {
+				t.Fatalf("ContainsDiff(...): -want diff, +got diff: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_NewNormalizerErrors(t *testing.T) {
+	if _, err := NewNormalizer(CompareOptions{IgnorePaths: []string{".items["}}); err == nil {
+		t.Fatalf("NewNormalizer(...): expected an error for an invalid path")
+	}
+
+	n, err := NewNormalizer(CompareOptions{Normalize: ".items"})
+	if err != nil {
+		t.Fatalf("NewNormalizer(...): unexpected error: %s", err)
+	}
+	if _, err := n.Normalize(map[string]interface{}{"items": []interface{}{}}); err == nil {
+		t.Fatalf("Normalize(...): expected an error for a result that is not an object")
+	}
+}
@@ -0,0 +1,77 @@
+package json
+
+import (
+	"encoding/json"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/pkg/errors"
+	jsonpatchgen "gomodules.xyz/jsonpatch/v2"
+)
+
+const (
+	errMarshalPatchDocument = "cannot marshal JSON document"
+	errCreatePatch          = "cannot create patch"
+)
+
+// MergePatch returns the RFC 7386 merge patch updating observed so that it
+// contains desired, following the semantics of Contains: the top level fields
+// of desired replace those of observed, the others are left untouched.
+func MergePatch(observed, desired map[string]interface{}) ([]byte, error) {
+	original, target, err := patchDocuments(observed, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	patch, err := jsonpatch.CreateMergePatch(original, target)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreatePatch)
+	}
+	return patch, nil
+}
+
+// JSONPatch returns the RFC 6902 JSON Patch updating observed so that it
+// contains desired, following the semantics of MergePatch.
+func JSONPatch(observed, desired map[string]interface{}) ([]byte, error) {
+	original, target, err := patchDocuments(observed, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	operations, err := jsonpatchgen.CreatePatch(original, target)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreatePatch)
+	}
+	if len(operations) == 0 {
+		return []byte("[]"), nil
+	}
+
+	patch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, errors.Wrap(err, errCreatePatch)
+	}
+	return patch, nil
+}
+
+// patchDocuments returns the encoded observed state and the state it is
+// patched to.
+func patchDocuments(observed, desired map[string]interface{}) ([]byte, []byte, error) {
+	target := make(map[string]interface{}, len(observed)+len(desired))
+	for key, value := range observed {
+		target[key] = value
+	}
+	for key, value := range desired {
+		target[key] = value
+	}
+
+	original, err := json.Marshal(observed)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errMarshalPatchDocument)
+	}
+
+	modified, err := json.Marshal(target)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, errMarshalPatchDocument)
+	}
+
+	return original, modified, nil
+}
@@ -0,0 +1,84 @@
+package json
+
+import (
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_Patches(t *testing.T) {
+	type args struct {
+		observed string
+		desired  string
+	}
+	type want struct {
+		mergePatch string
+		patched    string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"UpToDate": {
+			args: args{
+				observed: `{"id":"123","username":"john_doe"}`,
+				desired:  `{"username":"john_doe"}`,
+			},
+			want: want{
+				mergePatch: `{}`,
+				patched:    `{"id":"123","username":"john_doe"}`,
+			},
+		},
+		"TopLevel": {
+			args: args{
+				observed: `{"id":"123","username":"john_doe","updatedAt":"2024-01-01"}`,
+				desired:  `{"username":"jane_doe","email":"jane@example.com"}`,
+			},
+			want: want{
+				mergePatch: `{"email":"jane@example.com","username":"jane_doe"}`,
+				patched:    `{"id":"123","username":"jane_doe","email":"jane@example.com","updatedAt":"2024-01-01"}`,
+			},
+		},
+		"NestedAndArrays": {
+			args: args{
+				observed: `{"id":"123","settings":{"theme":"light","language":"en"},"roles":["admin","dev","ops"]}`,
+				desired:  `{"settings":{"theme":"dark"},"roles":["admin","qa"]}`,
+			},
+			want: want{
+				mergePatch: `{"roles":["admin","qa"],"settings":{"language":null,"theme":"dark"}}`,
+				patched:    `{"id":"123","settings":{"theme":"dark"},"roles":["admin","qa"]}`,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			observed := JsonStringToMap(tc.args.observed)
+			desired := JsonStringToMap(tc.args.desired)
+
+			mergePatch, err := MergePatch(observed, desired)
+			if err != nil {
+				t.Fatalf("MergePatch(...): unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(JsonStringToMap(tc.want.mergePatch), JsonStringToMap(string(mergePatch))); diff != "" {
+				t.Fatalf("MergePatch(...): -want patch, +got patch: %s", diff)
+			}
+
+			patch, err := JSONPatch(observed, desired)
+			if err != nil {
+				t.Fatalf("JSONPatch(...): unexpected error: %s", err)
+			}
+			decoded, err := jsonpatch.DecodePatch(patch)
+			if err != nil {
+				t.Fatalf("JSONPatch(...): invalid patch %s: %s", patch, err)
+			}
+			patched, err := decoded.Apply([]byte(tc.args.observed))
+			if err != nil {
+				t.Fatalf("JSONPatch(...): cannot apply patch %s: %s", patch, err)
+			}
+			if diff := cmp.Diff(JsonStringToMap(tc.want.patched), JsonStringToMap(string(patched))); diff != "" {
+				t.Fatalf("JSONPatch(...): -want patched, +got patched: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Package secrets resolves the Secret references of requests, written as
+// {{ secret:namespace:name:key }} placeholders.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	errGetSecret           = "cannot get secret %s/%s"
+	errMissingKey          = "key %s not found in secret %s/%s"
+	errNamespaceNotAllowed = "secret %s/%s cannot be referenced, only the secrets of namespace %s can"
+	errNewCache            = "cannot create the cache of the referenced secrets"
+	errAddCache            = "cannot add the cache of the referenced secrets"
+
+	// IndexKey is the field index of the Secrets referenced by an object,
+	// as namespace/name.
+	IndexKey = "secretRefs"
+)
+
+var placeholder = regexp.MustCompile(`\{\{\s*secret:([-._a-zA-Z0-9]+):([-._a-zA-Z0-9]+):([-._a-zA-Z0-9]+)\s*\}\}`)
+
+// Ref references a key of a Secret.
+type Ref struct {
+	Namespace string
+	Name      string
+	Key       string
+}
+
+// Refs returns the Secret keys referenced by the placeholders of values.
+func Refs(values ...string) []Ref {
+	var refs []Ref
+	seen := map[Ref]bool{}
+
+	for _, value := range values {
+		for _, match := range placeholder.FindAllStringSubmatch(value, -1) {
+			ref := Ref{Namespace: match[1], Name: match[2], Key: match[3]}
+			if !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+
+	return refs
+}
+
+// RequestStrings returns the strings of a request that may hold placeholders.
+func RequestStrings(url, body string, headers map[string][]string) []string {
+	values := []string{url, body}
+	for _, v := range headers {
+		values = append(values, v...)
+	}
+	return values
+}
+
+// Scope is the only namespace whose Secrets the placeholders may reference,
+// along with the cache watching its Secrets.
+type Scope struct {
+	Namespace string
+	Cache     cache.Cache
+}
+
+// NewScope returns the Scope of the given namespace, whose cache is started
+// along with mgr.
+func NewScope(mgr manager.Manager, namespace string) (Scope, error) {
+	c, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme:    mgr.GetScheme(),
+		Mapper:    mgr.GetRESTMapper(),
+		Namespace: namespace,
+	})
+	if err != nil {
+		return Scope{}, errors.Wrap(err, errNewCache)
+	}
+
+	if err := mgr.Add(c); err != nil {
+		return Scope{}, errors.Wrap(err, errAddCache)
+	}
+
+	return Scope{Namespace: namespace, Cache: c}, nil
+}
+
+// Resolver returns the Resolver of the Secrets of the scope.
+func (s Scope) Resolver() Resolver {
+	return Resolver{Namespace: s.Namespace, Reader: s.Cache}
+}
+
+// Resolver reads the Secret keys referenced by placeholders.
+type Resolver struct {
+	// Namespace is the only namespace whose Secrets may be referenced.
+	Namespace string
+
+	// Reader reads the Secrets of the namespace.
+	Reader client.Reader
+}
+
+// Values holds the values of resolved Secret references.
+type Values map[Ref]string
+
+// Resolve reads the Secret keys referenced by the placeholders of values. It
+// returns nil if values hold no placeholder, and an error if one of them
+// references a Secret outside of the namespace of the resolver.
+func (r Resolver) Resolve(ctx context.Context, values ...string) (Values, error) {
+	refs := Refs(values...)
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	for _, ref := range refs {
+		if ref.Namespace != r.Namespace {
+			return nil, errors.Errorf(errNamespaceNotAllowed, ref.Namespace, ref.Name, r.Namespace)
+		}
+	}
+
+	resolved := make(Values, len(refs))
+	secrets := map[types.NamespacedName]*corev1.Secret{}
+
+	for _, ref := range refs {
+		nn := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+
+		secret, ok := secrets[nn]
+		if !ok {
+			secret = &corev1.Secret{}
+			if err := r.Reader.Get(ctx, nn, secret); err != nil {
+				return nil, errors.Wrapf(err, errGetSecret, ref.Namespace, ref.Name)
+			}
+			secrets[nn] = secret
+		}
+
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, errors.Errorf(errMissingKey, ref.Key, ref.Namespace, ref.Name)
+		}
+
+		resolved[ref] = string(value)
+	}
+
+	return resolved, nil
+}
+
+// Interpolate replaces the placeholders of s with the values they reference.
+// Placeholders that were not resolved are left as is.
+func (v Values) Interpolate(s string) string {
+	return v.replace(s, func(value string, _ int) string { return value })
+}
+
+// InterpolateJSON replaces the placeholders of s, a JSON document, with the
+// values they reference escaped as JSON string content.
+func (v Values) InterpolateJSON(s string) string {
+	return v.replace(s, func(value string, _ int) string {
+		b, _ := json.Marshal(value)
+		return string(b[1 : len(b)-1])
+	})
+}
+
+// InterpolateURL replaces the placeholders of s, a URL, with the values they
+// reference escaped as a path segment, or as a query component once past the
+// start of the query.
+func (v Values) InterpolateURL(s string) string {
+	query := strings.IndexAny(s, "?#")
+	return v.replace(s, func(value string, at int) string {
+		if query >= 0 && at > query {
+			return url.QueryEscape(value)
+		}
+		return url.PathEscape(value)
+	})
+}
+
+// replace replaces the resolved placeholders of s with their value, escaped
+// according to the offset of the placeholder in s.
+func (v Values) replace(s string, escape func(value string, at int) string) string {
+	if len(v) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range placeholder.FindAllStringSubmatchIndex(s, -1) {
+		value, ok := v[Ref{Namespace: s[m[2]:m[3]], Name: s[m[4]:m[5]], Key: s[m[6]:m[7]]}]
+		if !ok {
+			continue
+		}
+		b.WriteString(s[last:m[0]])
+		b.WriteString(escape(value, m[0]))
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// IndexReferencedSecrets indexes an object by the Secrets its placeholders
+// reference, as namespace/name.
+func IndexReferencedSecrets(obj client.Object) []string {
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	seen := map[string]bool{}
+	for _, ref := range Refs(string(b)) {
+		key := ref.Namespace + "/" + ref.Name
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// EnqueueReferencing returns an event handler enqueuing the objects of the
+// list kind that reference the Secret of the event. The objects must be
+// indexed under IndexKey by IndexReferencedSecrets.
+func EnqueueReferencing(kube client.Reader, list client.ObjectList) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(secret client.Object) []reconcile.Request {
+		l, ok := list.DeepCopyObject().(client.ObjectList)
+		if !ok {
+			return nil
+		}
+
+		key := secret.GetNamespace() + "/" + secret.GetName()
+		if err := kube.List(context.Background(), l, client.MatchingFields{IndexKey: key}); err != nil {
+			return nil
+		}
+
+		items, err := meta.ExtractList(l)
+		if err != nil {
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(items))
+		for _, item := range items {
+			if obj, ok := item.(client.Object); ok {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+				}})
+			}
+		}
+
+		return requests
+	})
+}
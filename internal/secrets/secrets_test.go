@@ -0,0 +1,253 @@
+package secrets
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+var errBoom = errors.New("boom")
+
+func mockSecretGet(gets *int, data map[string][]byte) test.MockGetFn {
+	return func(_ context.Context, _ client.ObjectKey, obj client.Object) error {
+		*gets++
+		if secret, ok := obj.(*corev1.Secret); ok {
+			secret.Data = data
+		}
+		return nil
+	}
+}
+
+func Test_Refs(t *testing.T) {
+	type args struct {
+		values []string
+	}
+	type want struct {
+		refs []Ref
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoPlaceholder": {
+			args: args{
+				values: []string{"https://api.example.com/users", `{"name":"john"}`},
+			},
+			want: want{},
+		},
+		"Placeholders": {
+			args: args{
+				values: []string{
+					"https://api.example.com/users?key={{ secret:default:api:key }}",
+					`{"password":"{{secret:crossplane-system:db.creds:password}}","key":"{{ secret:default:api:key }}"}`,
+				},
+			},
+			want: want{
+				refs: []Ref{
+					{Namespace: "default", Name: "api", Key: "key"},
+					{Namespace: "crossplane-system", Name: "db.creds", Key: "password"},
+				},
+			},
+		},
+		"Malformed": {
+			args: args{
+				values: []string{"{{ secret:default:api }}", "{{ secret:default:api:key:extra }}"},
+			},
+			want: want{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Refs(tc.args.values...)
+			if diff := cmp.Diff(tc.want.refs, got); diff != "" {
+				t.Fatalf("Refs(...): -want refs, +got refs: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_Resolve(t *testing.T) {
+	data := map[string][]byte{"username": []byte("john"), "password": []byte(`s3"cr3t`)}
+
+	type args struct {
+		kube   func(gets *int) client.Reader
+		values []string
+	}
+	type want struct {
+		interpolated     string
+		interpolatedJSON string
+		gets             int
+		err              error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoPlaceholder": {
+			args: args{
+				kube:   func(gets *int) client.Reader { return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)} },
+				values: []string{`{"username":"john"}`},
+			},
+			want: want{
+				interpolated:     `{"username":"john"}`,
+				interpolatedJSON: `{"username":"john"}`,
+			},
+		},
+		"Resolved": {
+			args: args{
+				kube: func(gets *int) client.Reader {
+					return &test.MockClient{MockGet: mockSecretGet(gets, data)}
+				},
+				values: []string{`{"username":"{{ secret:default:creds:username }}","password":"{{ secret:default:creds:password }}"}`},
+			},
+			want: want{
+				interpolated:     `{"username":"john","password":"s3"cr3t"}`,
+				interpolatedJSON: `{"username":"john","password":"s3\"cr3t"}`,
+				gets:             1,
+			},
+		},
+		"MissingKey": {
+			args: args{
+				kube: func(gets *int) client.Reader {
+					return &test.MockClient{MockGet: mockSecretGet(gets, data)}
+				},
+				values: []string{"{{ secret:default:creds:token }}"},
+			},
+			want: want{
+				gets: 1,
+				err:  errors.Errorf(errMissingKey, "token", "default", "creds"),
+			},
+		},
+		"GetSecretFailed": {
+			args: args{
+				kube:   func(gets *int) client.Reader { return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)} },
+				values: []string{"{{ secret:default:creds:token }}"},
+			},
+			want: want{
+				err: errors.Wrapf(errBoom, errGetSecret, "default", "creds"),
+			},
+		},
+		"NamespaceNotAllowed": {
+			args: args{
+				kube: func(gets *int) client.Reader {
+					return &test.MockClient{MockGet: mockSecretGet(gets, data)}
+				},
+				values: []string{"{{ secret:default:creds:username }}", "{{ secret:kube-system:creds:token }}"},
+			},
+			want: want{
+				err: errors.Errorf(errNamespaceNotAllowed, "kube-system", "creds", "default"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			gets := 0
+			r := Resolver{Namespace: "default", Reader: tc.args.kube(&gets)}
+			values, err := r.Resolve(context.Background(), tc.args.values...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Resolve(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.gets, gets); diff != "" {
+				t.Fatalf("Resolve(...): -want secret gets, +got secret gets: %s", diff)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if diff := cmp.Diff(tc.want.interpolated, values.Interpolate(tc.args.values[0])); diff != "" {
+				t.Fatalf("Interpolate(...): -want, +got: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.interpolatedJSON, values.InterpolateJSON(tc.args.values[0])); diff != "" {
+				t.Fatalf("InterpolateJSON(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_InterpolateURL(t *testing.T) {
+	values := Values{
+		{Namespace: "default", Name: "creds", Key: "id"}:    "a/b c",
+		{Namespace: "default", Name: "creds", Key: "token"}: "t0k&n=1",
+	}
+
+	cases := map[string]struct {
+		url  string
+		want string
+	}{
+		"Path": {
+			url:  "https://api.example.com/users/{{ secret:default:creds:id }}",
+			want: "https://api.example.com/users/a%2Fb%20c",
+		},
+		"Query": {
+			url:  "https://api.example.com/users?token={{ secret:default:creds:token }}&id={{ secret:default:creds:id }}",
+			want: "https://api.example.com/users?token=t0k%26n%3D1&id=a%2Fb+c",
+		},
+		"PathAndQuery": {
+			url:  "https://api.example.com/users/{{ secret:default:creds:id }}?token={{ secret:default:creds:token }}",
+			want: "https://api.example.com/users/a%2Fb%20c?token=t0k%26n%3D1",
+		},
+		"Unresolved": {
+			url:  "https://api.example.com/users/{{ secret:default:other:id }}?token={{ secret:default:creds:token }}",
+			want: "https://api.example.com/users/{{ secret:default:other:id }}?token=t0k%26n%3D1",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, values.InterpolateURL(tc.url)); diff != "" {
+				t.Fatalf("InterpolateURL(...): -want, +got: %s", diff)
+			}
+		})
+	}
+}
+
+func Test_IndexReferencedSecrets(t *testing.T) {
+	type args struct {
+		obj client.Object
+	}
+	type want struct {
+		keys []string
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoReference": {
+			args: args{
+				obj: &corev1.ConfigMap{Data: map[string]string{"body": `{"name":"john"}`}},
+			},
+			want: want{},
+		},
+		"References": {
+			args: args{
+				obj: &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "request"},
+					Data: map[string]string{
+						"body":   `{"password":"{{ secret:default:creds:password }}","username":"{{ secret:default:creds:username }}"}`,
+						"header": "Bearer {{ secret:crossplane-system:token:value }}",
+					},
+				},
+			},
+			want: want{
+				keys: []string{"default/creds", "crossplane-system/token"},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := IndexReferencedSecrets(tc.args.obj)
+			if diff := cmp.Diff(tc.want.keys, got); diff != "" {
+				t.Fatalf("IndexReferencedSecrets(...): -want keys, +got keys: %s", diff)
+			}
+		})
+	}
+}
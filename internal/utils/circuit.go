@@ -0,0 +1,16 @@
+package utils
+
+import (
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	corev1 "k8s.io/api/core/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+)
+
+// SetCircuitClosed marks the circuit of the resource closed if it was open,
+// once one of its requests reached its host again.
+func SetCircuitClosed(cr resource.Conditioned) {
+	if cr.GetCondition(apisv1alpha1.TypeCircuitOpen).Status == corev1.ConditionTrue {
+		cr.SetConditions(apisv1alpha1.CircuitClosed())
+	}
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+)
+
+func Test_SetCircuitClosed(t *testing.T) {
+	cases := map[string]struct {
+		conditions []xpv1.Condition
+		want       xpv1.ConditionReason
+	}{
+		"NeverOpen": {
+			want: "",
+		},
+		"Open": {
+			conditions: []xpv1.Condition{apisv1alpha1.CircuitOpen(errors.New("circuit open"))},
+			want:       apisv1alpha1.ReasonCircuitClosed,
+		},
+		"Closed": {
+			conditions: []xpv1.Condition{apisv1alpha1.CircuitClosed()},
+			want:       apisv1alpha1.ReasonCircuitClosed,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &fake.Managed{}
+			cr.SetConditions(tc.conditions...)
+
+			SetCircuitClosed(cr)
+
+			got := cr.GetCondition(apisv1alpha1.TypeCircuitOpen)
+			if diff := cmp.Diff(tc.want, got.Reason); diff != "" {
+				t.Fatalf("SetCircuitClosed(...): -want reason, +got reason: %s", diff)
+			}
+			if tc.want != "" && got.Status != corev1.ConditionFalse {
+				t.Fatalf("SetCircuitClosed(...): want the circuit closed, got status %s", got.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"encoding/json"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/pkg/errors"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+	"github.com/crossplane-contrib/provider-http/internal/jq"
+)
+
+const (
+	errConnectionDetail = "cannot extract connection detail %s"
+)
+
+// ResponseObject returns the jq object of response: its statusCode, headers
+// and body, parsed when it is JSON.
+func ResponseObject(response httpClient.HttpResponse) map[string]interface{} {
+	var body interface{} = response.Body
+	var parsed interface{}
+	if err := json.Unmarshal([]byte(response.Body), &parsed); err == nil {
+		body = parsed
+	}
+
+	headers := make(map[string]interface{}, len(response.Headers))
+	for name, values := range response.Headers {
+		v := make([]interface{}, len(values))
+		for i, value := range values {
+			v[i] = value
+		}
+		headers[name] = v
+	}
+
+	return map[string]interface{}{
+		"statusCode": response.StatusCode,
+		"headers":    headers,
+		"body":       body,
+	}
+}
+
+// ConnectionDetails extracts the connection details from response. Details
+// whose expression returns null are skipped, so the values already published
+// for them are kept.
+func ConnectionDetails(details []apisv1alpha1.ConnectionDetail, response httpClient.HttpResponse) (managed.ConnectionDetails, error) {
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	obj := ResponseObject(response)
+	connectionDetails := managed.ConnectionDetails{}
+	for _, d := range details {
+		value, err := jq.ParseValue(d.Expression, obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, errConnectionDetail, d.Key)
+		}
+
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			connectionDetails[d.Key] = []byte(v)
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, errors.Wrapf(err, errConnectionDetail, d.Key)
+			}
+			connectionDetails[d.Key] = b
+		}
+	}
+
+	return connectionDetails, nil
+}
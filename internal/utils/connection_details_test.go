@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	apisv1alpha1 "github.com/crossplane-contrib/provider-http/apis/v1alpha1"
+	httpClient "github.com/crossplane-contrib/provider-http/internal/clients/http"
+)
+
+func Test_ConnectionDetails(t *testing.T) {
+	response := httpClient.HttpResponse{
+		StatusCode: 201,
+		Headers:    map[string][]string{"X-Api-Key": {"k3y"}},
+		Body:       `{"id":123,"credentials":{"username":"john","password":"s3cr3t"},"scopes":["read","write"]}`,
+	}
+
+	type args struct {
+		details  []apisv1alpha1.ConnectionDetail
+		response httpClient.HttpResponse
+	}
+	type want struct {
+		connectionDetails managed.ConnectionDetails
+		err               error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NoConnectionDetails": {
+			args: args{
+				response: response,
+			},
+			want: want{},
+		},
+		"BodyAndHeaders": {
+			args: args{
+				details: []apisv1alpha1.ConnectionDetail{
+					{Key: "username", Expression: ".body.credentials.username"},
+					{Key: "password", Expression: ".body.credentials.password"},
+					{Key: "apiKey", Expression: `.headers["X-Api-Key"][0]`},
+					{Key: "id", Expression: ".body.id"},
+					{Key: "scopes", Expression: ".body.scopes"},
+					{Key: "status", Expression: ".statusCode"},
+					{Key: "token", Expression: ".body.token"},
+				},
+				response: response,
+			},
+			want: want{
+				connectionDetails: managed.ConnectionDetails{
+					"username": []byte("john"),
+					"password": []byte("s3cr3t"),
+					"apiKey":   []byte("k3y"),
+					"id":       []byte("123"),
+					"scopes":   []byte(`["read","write"]`),
+					"status":   []byte("201"),
+				},
+			},
+		},
+		"NotJSONBody": {
+			args: args{
+				details:  []apisv1alpha1.ConnectionDetail{{Key: "token", Expression: ".body"}},
+				response: httpClient.HttpResponse{StatusCode: 200, Body: "t0k3n"},
+			},
+			want: want{
+				connectionDetails: managed.ConnectionDetails{"token": []byte("t0k3n")},
+			},
+		},
+		"InvalidExpression": {
+			args: args{
+				details:  []apisv1alpha1.ConnectionDetail{{Key: "token", Expression: ".body.scopes.token"}},
+				response: response,
+			},
+			want: want{
+				err: errors.Wrapf(errors.Errorf("failed to parse given mapping - %s jq error: %s", ".body.scopes.token", "expected an object but got: array ([\"read\",\"write\"])"), errConnectionDetail, "token"),
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ConnectionDetails(tc.args.details, tc.args.response)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("ConnectionDetails(...): -want error, +got error: %s", diff)
+			}
+
+			if diff := cmp.Diff(tc.want.connectionDetails, got); diff != "" {
+				t.Fatalf("ConnectionDetails(...): -want connection details, +got connection details: %s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Requeues records the resources to requeue sooner than their poll interval,
+// e.g. once the rate limits of their host held back their requests.
+type Requeues struct {
+	mu    sync.Mutex
+	after map[types.NamespacedName]time.Duration
+}
+
+// NewRequeues returns an empty Requeues.
+func NewRequeues() *Requeues {
+	return &Requeues{after: map[types.NamespacedName]time.Duration{}}
+}
+
+// RequeueAfter requeues the resource after the given delay once it is
+// reconciled. Nothing is recorded on a nil Requeues.
+func (r *Requeues) RequeueAfter(o client.Object, after time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.after[types.NamespacedName{Namespace: o.GetNamespace(), Name: o.GetName()}] = after
+}
+
+func (r *Requeues) pop(name types.NamespacedName) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	after, ok := r.after[name]
+	delete(r.after, name)
+	return after, ok
+}
+
+// Reconciler returns a reconciler requeuing the resources recorded while rec
+// reconciled them after their recorded delay, unless rec requeues them sooner.
+func (r *Requeues) Reconciler(rec reconcile.Reconciler) reconcile.Reconciler {
+	return reconcile.Func(func(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+		result, err := rec.Reconcile(ctx, req)
+
+		after, ok := r.pop(req.NamespacedName)
+		if !ok || err != nil {
+			return result, err
+		}
+
+		if result.RequeueAfter == 0 || after < result.RequeueAfter {
+			result.RequeueAfter = after
+		}
+		return result, nil
+	})
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func Test_RequeuesReconciler(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		after  time.Duration
+		result reconcile.Result
+		err    error
+	}
+	type want struct {
+		result reconcile.Result
+		err    error
+	}
+	cases := map[string]struct {
+		args args
+		want want
+	}{
+		"NotRequeued": {
+			args: args{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Minute},
+			},
+		},
+		"RequeuedSooner": {
+			args: args{
+				after:  5 * time.Second,
+				result: reconcile.Result{RequeueAfter: time.Minute},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: 5 * time.Second},
+			},
+		},
+		"RequeuedAfterBackoff": {
+			args: args{
+				after:  5 * time.Second,
+				result: reconcile.Result{Requeue: true},
+			},
+			want: want{
+				result: reconcile.Result{Requeue: true, RequeueAfter: 5 * time.Second},
+			},
+		},
+		"ReconcilerRequeuesSooner": {
+			args: args{
+				after:  time.Minute,
+				result: reconcile.Result{RequeueAfter: 5 * time.Second},
+			},
+			want: want{
+				result: reconcile.Result{RequeueAfter: 5 * time.Second},
+			},
+		},
+		"Error": {
+			args: args{
+				after: 5 * time.Second,
+				err:   errBoom,
+			},
+			want: want{
+				err: errBoom,
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := NewRequeues()
+			rec := r.Reconciler(reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+				if tc.args.after != 0 {
+					mg := &fake.Managed{}
+					mg.SetName("request")
+					r.RequeueAfter(mg, tc.args.after)
+				}
+				return tc.args.result, tc.args.err
+			}))
+
+			got, err := rec.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "request"}})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("Reconcile(...): -want error, +got error: %s", diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Fatalf("Reconcile(...): -want result, +got result: %s", diff)
+			}
+
+			if _, ok := r.pop(types.NamespacedName{Name: "request"}); ok {
+				t.Fatalf("Reconcile(...): want the requeue forgotten once reconciled")
+			}
+		})
+	}
+}
@@ -19,6 +19,26 @@ type RequestResource struct {
 	HttpResponse   httpClient.HttpResponse
 	HttpRequest    httpClient.HttpRequest
 	LocalClient    client.Client
+
+	// Masker, when set, hides values of the request and response before
+	// they are written to the status.
+	Masker *httpClient.Redactor
+}
+
+// maskedRequest returns the request written to the status.
+func (rr *RequestResource) maskedRequest() httpClient.HttpRequest {
+	if rr.Masker == nil {
+		return rr.HttpRequest
+	}
+	return rr.Masker.Request(rr.HttpRequest)
+}
+
+// maskedResponse returns the response written to the status.
+func (rr *RequestResource) maskedResponse() httpClient.HttpResponse {
+	if rr.Masker == nil {
+		return rr.HttpResponse
+	}
+	return rr.Masker.Response(rr.HttpResponse)
 }
 
 func (rr *RequestResource) SetStatusCode() SetRequestStatusFunc {
@@ -35,7 +55,7 @@ func (rr *RequestResource) SetHeaders() SetRequestStatusFunc {
 	return func() {
 		if resp, ok := rr.Resource.(ResponseSetter); ok {
 			if rr.HttpResponse.Headers != nil {
-				resp.SetHeaders(rr.HttpResponse.Headers)
+				resp.SetHeaders(rr.maskedResponse().Headers)
 			}
 		}
 	}
@@ -45,7 +65,7 @@ func (rr *RequestResource) SetBody() SetRequestStatusFunc {
 	return func() {
 		if resp, ok := rr.Resource.(ResponseSetter); ok {
 			if rr.HttpResponse.Body != "" {
-				resp.SetBody(rr.HttpResponse.Body)
+				resp.SetBody(rr.maskedResponse().Body)
 			}
 		}
 	}
@@ -55,7 +75,8 @@ func (rr *RequestResource) SetRequestDetails() SetRequestStatusFunc {
 	return func() {
 		if resp, ok := rr.Resource.(RequestDetailsSetter); ok {
 			if rr.HttpRequest.Method != "" {
-				resp.SetRequestDetails(rr.HttpRequest.URL, rr.HttpRequest.Method, rr.HttpRequest.Body, rr.HttpRequest.Headers)
+				request := rr.maskedRequest()
+				resp.SetRequestDetails(request.URL, request.Method, request.Body, request.Proxy, request.Headers)
 			}
 		}
 	}
@@ -72,7 +93,8 @@ func (rr *RequestResource) SetSynced() SetRequestStatusFunc {
 func (rr *RequestResource) SetCache() SetRequestStatusFunc {
 	return func() {
 		if cached, ok := rr.Resource.(CacheSetter); ok {
-			cached.SetCache(rr.HttpResponse.StatusCode, rr.HttpResponse.Headers, rr.HttpResponse.Body)
+			response := rr.maskedResponse()
+			cached.SetCache(response.StatusCode, response.Headers, response.Body)
 		}
 	}
 }
@@ -116,7 +138,7 @@ type ResetFailures interface {
 }
 
 type RequestDetailsSetter interface {
-	SetRequestDetails(url, method, body string, headers map[string][]string)
+	SetRequestDetails(url, method, body, proxy string, headers map[string][]string)
 }
 
 func SetRequestResourceStatus(rr RequestResource, statusFuncs ...SetRequestStatusFunc) error {
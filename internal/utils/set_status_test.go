@@ -275,3 +275,55 @@ func Test_DisposableRequest_SetRequestResourceStatus(t *testing.T) {
 		})
 	}
 }
+
+func Test_SetRequestResourceStatusMasked(t *testing.T) {
+	masker, err := httpClient.NewMasker(httpClient.RedactionConfig{Headers: []string{"X-Token"}, BodyPaths: []string{".token"}})
+	if err != nil {
+		t.Fatalf("NewMasker(...): unexpected error: %s", err)
+	}
+
+	cr := &v1alpha1_disposable.DisposableRequest{}
+	rr := RequestResource{
+		Resource:       cr,
+		RequestContext: context.Background(),
+		HttpResponse: httpClient.HttpResponse{
+			StatusCode: 200,
+			Body:       `{"id":"123","token":"t0k3n"}`,
+			Headers:    map[string][]string{"X-Token": {"t0k3n"}},
+		},
+		HttpRequest: httpClient.HttpRequest{
+			Method:  "POST",
+			URL:     "http://example",
+			Body:    `{"token":"t0k3n"}`,
+			Headers: map[string][]string{"X-Token": {"t0k3n"}},
+		},
+		LocalClient: &test.MockClient{
+			MockStatusUpdate: test.NewMockSubResourceUpdateFn(nil),
+		},
+		Masker: masker,
+	}
+
+	if err := SetRequestResourceStatus(rr, rr.SetBody(), rr.SetHeaders(), rr.SetRequestDetails()); err != nil {
+		t.Fatalf("SetRequestResourceStatus(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(`{"id":"123","token":"***"}`, cr.Status.Response.Body); diff != "" {
+		t.Fatalf("SetRequestResourceStatus(...): -want response body, +got response body: %s", diff)
+	}
+
+	if diff := cmp.Diff(map[string][]string{"X-Token": {"***"}}, cr.Status.Response.Headers); diff != "" {
+		t.Fatalf("SetRequestResourceStatus(...): -want response headers, +got response headers: %s", diff)
+	}
+
+	if diff := cmp.Diff(`{"token":"***"}`, cr.Status.RequestDetails.Body); diff != "" {
+		t.Fatalf("SetRequestResourceStatus(...): -want request body, +got request body: %s", diff)
+	}
+
+	if diff := cmp.Diff(map[string][]string{"X-Token": {"***"}}, cr.Status.RequestDetails.Headers); diff != "" {
+		t.Fatalf("SetRequestResourceStatus(...): -want request headers, +got request headers: %s", diff)
+	}
+
+	if diff := cmp.Diff(`{"id":"123","token":"t0k3n"}`, rr.HttpResponse.Body); diff != "" {
+		t.Fatalf("SetRequestResourceStatus(...): -want unmasked response body, +got unmasked response body: %s", diff)
+	}
+}